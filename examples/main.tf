@@ -13,16 +13,25 @@ resource "kibana_actions_connector" "sample_connector" {
 }
 
 resource "kibana_actions_connector" "sample_connector_slack" {
-  connector_type_id = ".slack"
-  name              = "test_connector_created_with_custom_provider_for_slack"
+  name = "test_connector_created_with_custom_provider_for_slack"
 
-  config = jsonencode({})
+  slack {
+    webhook_url = "https://abcd.com"
+  }
+}
 
-  secrets = jsonencode(
-    {
-      "webhookUrl" : "https://abcd.com",
+# sends a test message on every plan and apply when test_connectors is set
+data "kibana_actions_connector_execution" "sample_connector_slack_test" {
+  connector_id = kibana_actions_connector.sample_connector_slack.id
+  params       = jsonencode({ "message" : "Test message sent by Terraform" })
+  execute      = var.test_connectors
+
+  lifecycle {
+    postcondition {
+      condition     = !var.test_connectors || self.status == "ok"
+      error_message = "The Slack connector is broken: ${self.message} ${self.service_message}"
     }
-  )
+  }
 }
 
 resource "kibana_alerting_rule" "sample_rule" {
@@ -57,39 +66,48 @@ resource "kibana_alerting_rule" "sample_rule" {
     )
   }
 
-  consumer    = "alerts"
-  enabled     = true
-  name        = "my-terraform-rule"
-  notify_when = "onActiveAlert"
-  param_es_query = jsonencode(
-    {
-      "query" : {
-        "bool" : {
-          "filter" : [
-            {
-              "bool" : {
-                "should" : [{ "range" : { "value.count" : { "gt" : "0" } } }],
-                "minimum_should_match" : 1
+  consumer          = "alerts"
+  enabled           = true
+  name              = "my-terraform-rule"
+  notify_when       = "onActiveAlert"
+  schedule_interval = "5m"
+  tags              = ["tag1", "tag2", "tag3"]
+
+  es_query {
+    es_query = jsonencode(
+      {
+        "query" : {
+          "bool" : {
+            "filter" : [
+              {
+                "bool" : {
+                  "should" : [{ "range" : { "value.count" : { "gt" : "0" } } }],
+                  "minimum_should_match" : 1
+                }
+              },
+              { "match_phrase" : { "namespace.keyword" : "AWS/SQS" } },
+              {
+                "match_phrase" : { "metric_name.keyword" : "NumberOfMessagesReceived" }
               }
-            },
-            { "match_phrase" : { "namespace.keyword" : "AWS/SQS" } },
-            {
-              "match_phrase" : { "metric_name.keyword" : "NumberOfMessagesReceived" }
-            }
-          ]
+            ]
+          }
         }
       }
-    }
-  )
-  param_index                = ["my-index*"]
-  param_size                 = 1
-  param_threshold            = [1]
-  param_threshold_comparator = ">"
-  param_time_field           = "timestamp"
-  param_time_window_size     = 5
-  param_time_window_unit     = "m"
-  rule_type_id               = ".es-query"
-  schedule_interval          = "5m"
-  tags                       = ["tag1", "tag2", "tag3"]
+    )
+    index                = ["my-index*"]
+    size                 = 1
+    threshold            = [1]
+    threshold_comparator = ">"
+    time_field           = "timestamp"
+    time_window_size     = 5
+    time_window_unit     = "m"
+  }
 }
 
+resource "kibana_maintenance_window" "weekly_release" {
+  title    = "Weekly release"
+  start    = "2030-01-05T22:00:00Z"
+  duration = "2h"
+  rrule    = "FREQ=WEEKLY;BYDAY=SA"
+  timezone = "Europe/Lisbon"
+}
@@ -22,3 +22,9 @@ variable "kibana_space" {
   description = "Kibana space to use"
   type        = string
 }
+
+variable "test_connectors" {
+  description = "Runs the connectors on every plan to check that they work"
+  type        = bool
+  default     = false
+}
@@ -0,0 +1,116 @@
+package kibana
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	gk "github.com/renato0307/go-kibana/kibana"
+)
+
+// apiClient - Kibana client shared by the provider resources
+//
+// It extends the go-kibana client with the Kibana version detected when the
+// provider is configured and with the APIs the go-kibana client does not cover.
+type apiClient struct {
+	*gk.Client
+	version string
+}
+
+// withSpace - returns a copy of the client using the given Kibana space
+func (c *apiClient) withSpace(space string) *apiClient {
+	if space == "" || space == c.Space {
+		return c
+	}
+
+	client := *c.Client
+	client.Space = space
+	return &apiClient{Client: &client, version: c.version}
+}
+
+// resourceClient - returns the client for the space of a resource, which is
+// the resource space_id when set or the provider space otherwise
+func resourceClient(d interface{ Get(string) interface{} }, m interface{}) *apiClient {
+	c := m.(*apiClient)
+	return c.withSpace(d.Get("space_id").(string))
+}
+
+// resourceImportSpaceObject - imports a space scoped object using an ID with
+// the format <space_id>/<object_id>, or just <object_id> for objects in the
+// provider space
+func resourceImportSpaceObject(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		return []*schema.ResourceData{d}, nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		_ = d.Set("space_id", parts[0])
+		d.SetId(parts[1])
+		return []*schema.ResourceData{d}, nil
+	}
+
+	return nil, fmt.Errorf("unexpected import ID %q, expected <space_id>/<object_id> or <object_id>", d.Id())
+}
+
+// apiError - error returned when Kibana answers with an unsuccessful status
+type apiError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("status: %d, body: %s", e.StatusCode, e.Body)
+}
+
+// isNotFound - checks if an error is a 404 response from Kibana
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusNotFound
+	}
+
+	// the go-kibana client only returns the status in the error message
+	return err != nil && strings.HasPrefix(err.Error(), fmt.Sprintf("status: %d,", http.StatusNotFound))
+}
+
+// resourceNotFound - removes an object deleted outside Terraform from the
+// state, so it is planned for creation again
+func resourceNotFound(d *schema.ResourceData, kind string) diag.Diagnostics {
+	id := d.Id()
+	d.SetId("")
+
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("The %s %s no longer exists", kind, id),
+			Detail:   fmt.Sprintf("The %s was deleted outside Terraform and was removed from the state", kind),
+		},
+	}
+}
+
+// doRequest - sends a request to Kibana using the client credentials
+func (c *apiClient) doRequest(req *http.Request) ([]byte, error) {
+	req.SetBasicAuth(c.Username, c.Password)
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return nil, &apiError{StatusCode: res.StatusCode, Body: body}
+	}
+
+	return body, nil
+}
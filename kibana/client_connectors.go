@@ -0,0 +1,353 @@
+package kibana
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	gk "github.com/renato0307/go-kibana/kibana"
+)
+
+// legacyConnector - connector as used by the /api/actions/action API of Kibana
+// versions older than 7.13
+type legacyConnector struct {
+	ID              string                 `json:"id,omitempty"`
+	Name            string                 `json:"name"`
+	ActionTypeID    string                 `json:"actionTypeId,omitempty"`
+	IsPreconfigured bool                   `json:"isPreconfigured,omitempty"`
+	Config          map[string]interface{} `json:"config,omitempty"`
+	Secrets         map[string]interface{} `json:"secrets,omitempty"`
+}
+
+func (l legacyConnector) toConnector() *gk.Connector {
+	return &gk.Connector{
+		ID:              l.ID,
+		Name:            l.Name,
+		ConnectorTypeId: l.ActionTypeID,
+		IsPreconfigured: l.IsPreconfigured,
+		Config:          l.Config,
+		Secrets:         l.Secrets,
+	}
+}
+
+// connectorTypeInfo - connector type offered by the server
+type connectorTypeInfo struct {
+	ID                     string   `json:"id"`
+	Name                   string   `json:"name"`
+	Enabled                bool     `json:"enabled"`
+	EnabledInConfig        bool     `json:"enabled_in_config"`
+	EnabledInLicense       bool     `json:"enabled_in_license"`
+	MinimumLicenseRequired string   `json:"minimum_license_required"`
+	SupportedFeatureIDs    []string `json:"supported_feature_ids"`
+}
+
+// legacyConnectorTypeInfo - connector type as used by the
+// /api/actions/list_action_types API of Kibana versions older than 7.13
+type legacyConnectorTypeInfo struct {
+	ID                     string `json:"id"`
+	Name                   string `json:"name"`
+	Enabled                bool   `json:"enabled"`
+	EnabledInConfig        bool   `json:"enabledInConfig"`
+	EnabledInLicense       bool   `json:"enabledInLicense"`
+	MinimumLicenseRequired string `json:"minimumLicenseRequired"`
+}
+
+func (l legacyConnectorTypeInfo) toConnectorTypeInfo() connectorTypeInfo {
+	return connectorTypeInfo{
+		ID:                     l.ID,
+		Name:                   l.Name,
+		Enabled:                l.Enabled,
+		EnabledInConfig:        l.EnabledInConfig,
+		EnabledInLicense:       l.EnabledInLicense,
+		MinimumLicenseRequired: l.MinimumLicenseRequired,
+	}
+}
+
+// getConnectorTypes - Retrieves the connector types offered by the server.
+// Check https://www.elastic.co/guide/en/kibana/7.13/list-connector-types-api.html
+func (c *apiClient) getConnectorTypes() ([]connectorTypeInfo, error) {
+	path := "api/actions/connector_types"
+	if !c.supports(versionAlertingAPI) {
+		path = "api/actions/list_action_types"
+	}
+
+	url := fmt.Sprintf("%s/s/%s/%s", c.HostURL, c.Space, path)
+	log.Printf("Getting connector types using %s", url)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.supports(versionAlertingAPI) {
+		types := []connectorTypeInfo{}
+		err = json.Unmarshal(body, &types)
+		return types, err
+	}
+
+	legacyTypes := []legacyConnectorTypeInfo{}
+	err = json.Unmarshal(body, &legacyTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	types := make([]connectorTypeInfo, 0, len(legacyTypes))
+	for _, t := range legacyTypes {
+		types = append(types, t.toConnectorTypeInfo())
+	}
+	return types, nil
+}
+
+// connectorInfo - connector as returned by the list connectors API
+type connectorInfo struct {
+	ID                string                 `json:"id"`
+	Name              string                 `json:"name"`
+	ConnectorTypeID   string                 `json:"connector_type_id"`
+	IsPreconfigured   bool                   `json:"is_preconfigured"`
+	IsDeprecated      bool                   `json:"is_deprecated"`
+	IsMissingSecrets  bool                   `json:"is_missing_secrets"`
+	Config            map[string]interface{} `json:"config"`
+	ReferencedByCount int                    `json:"referenced_by_count"`
+}
+
+// legacyConnectorInfo - connector as returned by the /api/actions API of
+// Kibana versions older than 7.13
+type legacyConnectorInfo struct {
+	ID                string                 `json:"id"`
+	Name              string                 `json:"name"`
+	ActionTypeID      string                 `json:"actionTypeId"`
+	IsPreconfigured   bool                   `json:"isPreconfigured"`
+	Config            map[string]interface{} `json:"config"`
+	ReferencedByCount int                    `json:"referencedByCount"`
+}
+
+func (l legacyConnectorInfo) toConnectorInfo() connectorInfo {
+	return connectorInfo{
+		ID:                l.ID,
+		Name:              l.Name,
+		ConnectorTypeID:   l.ActionTypeID,
+		IsPreconfigured:   l.IsPreconfigured,
+		Config:            l.Config,
+		ReferencedByCount: l.ReferencedByCount,
+	}
+}
+
+// getConnectors - Retrieves all connectors of the space, including the
+// preconfigured ones.
+// Check https://www.elastic.co/guide/en/kibana/7.13/get-all-connectors-api.html
+func (c *apiClient) getConnectors() ([]connectorInfo, error) {
+	path := "api/actions/connectors"
+	if !c.supports(versionAlertingAPI) {
+		path = "api/actions"
+	}
+
+	url := fmt.Sprintf("%s/s/%s/%s", c.HostURL, c.Space, path)
+	log.Printf("Getting connectors using %s", url)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.supports(versionAlertingAPI) {
+		connectors := []connectorInfo{}
+		err = json.Unmarshal(body, &connectors)
+		return connectors, err
+	}
+
+	legacyConnectors := []legacyConnectorInfo{}
+	err = json.Unmarshal(body, &legacyConnectors)
+	if err != nil {
+		return nil, err
+	}
+
+	connectors := make([]connectorInfo, 0, len(legacyConnectors))
+	for _, l := range legacyConnectors {
+		connectors = append(connectors, l.toConnectorInfo())
+	}
+	return connectors, nil
+}
+
+// connectorExecution - result of running a connector
+type connectorExecution struct {
+	ConnectorID    string      `json:"connector_id"`
+	Status         string      `json:"status"`
+	Message        string      `json:"message"`
+	ServiceMessage string      `json:"service_message"`
+	Data           interface{} `json:"data"`
+}
+
+// legacyConnectorExecution - result of running a connector with the
+// /api/actions/action API of Kibana versions older than 7.13
+type legacyConnectorExecution struct {
+	ActionID       string      `json:"actionId"`
+	Status         string      `json:"status"`
+	Message        string      `json:"message"`
+	ServiceMessage string      `json:"serviceMessage"`
+	Data           interface{} `json:"data"`
+}
+
+func (l legacyConnectorExecution) toConnectorExecution() *connectorExecution {
+	return &connectorExecution{
+		ConnectorID:    l.ActionID,
+		Status:         l.Status,
+		Message:        l.Message,
+		ServiceMessage: l.ServiceMessage,
+		Data:           l.Data,
+	}
+}
+
+// executeConnector - Runs a connector with the given params.
+// Check https://www.elastic.co/guide/en/kibana/7.13/execute-connector-api.html
+func (c *apiClient) executeConnector(connectorID string, params map[string]interface{}) (*connectorExecution, error) {
+	path := "api/actions/connector"
+	if !c.supports(versionAlertingAPI) {
+		path = "api/actions/action"
+	}
+
+	rb, err := json.Marshal(map[string]interface{}{"params": params})
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/s/%s/%s/%s/_execute", c.HostURL, c.Space, path, connectorID)
+	log.Printf("Executing connector using %s", url)
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("kbn-xsrf", "true")
+	req.Header.Set("content-type", "application/json")
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.supports(versionAlertingAPI) {
+		execution := connectorExecution{}
+		err = json.Unmarshal(body, &execution)
+		return &execution, err
+	}
+
+	execution := legacyConnectorExecution{}
+	err = json.Unmarshal(body, &execution)
+	if err != nil {
+		return nil, err
+	}
+	return execution.toConnectorExecution(), nil
+}
+
+// GetConnector - Retrieves a connector by ID using the API of the server version.
+func (c *apiClient) GetConnector(connectorID string) (*gk.Connector, error) {
+	if c.supports(versionAlertingAPI) {
+		return c.Client.GetConnector(connectorID)
+	}
+
+	url := fmt.Sprintf("%s/s/%s/api/actions/action/%s", c.HostURL, c.Space, connectorID)
+	log.Printf("Calling %s", url)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doLegacyConnectorRequest(req)
+}
+
+// CreateConnector - Creates a connector using the API of the server version.
+func (c *apiClient) CreateConnector(connector gk.CreateConnector) (*gk.Connector, error) {
+	if c.supports(versionAlertingAPI) {
+		return c.Client.CreateConnector(connector)
+	}
+
+	rb, err := json.Marshal(legacyConnector{
+		Name:         connector.Name,
+		ActionTypeID: connector.ConnectorTypeId,
+		Config:       connector.Config,
+		Secrets:      connector.Secrets,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/s/%s/api/actions/action", c.HostURL, c.Space)
+	log.Printf("Creating connector using URL %s", url)
+	req, err := http.NewRequest("POST", url, strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("kbn-xsrf", "true")
+	req.Header.Set("content-type", "application/json")
+
+	return c.doLegacyConnectorRequest(req)
+}
+
+// UpdateConnector - Updates an existing connector using the API of the server version.
+func (c *apiClient) UpdateConnector(connectorID string, connector gk.UpdateConnector) (*gk.Connector, error) {
+	if c.supports(versionAlertingAPI) {
+		return c.Client.UpdateConnector(connectorID, connector)
+	}
+
+	rb, err := json.Marshal(connector)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/s/%s/api/actions/action/%s", c.HostURL, c.Space, connectorID)
+	log.Printf("Calling %s", url)
+	req, err := http.NewRequest("PUT", url, strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("kbn-xsrf", "true")
+	req.Header.Set("content-type", "application/json")
+
+	return c.doLegacyConnectorRequest(req)
+}
+
+// DeleteConnector - Deletes a connector by ID using the API of the server version.
+func (c *apiClient) DeleteConnector(connectorID string) error {
+	if c.supports(versionAlertingAPI) {
+		return c.Client.DeleteConnector(connectorID)
+	}
+
+	url := fmt.Sprintf("%s/s/%s/api/actions/action/%s", c.HostURL, c.Space, connectorID)
+	log.Printf("Calling %s", url)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("kbn-xsrf", "true")
+
+	_, err = c.doRequest(req)
+	return err
+}
+
+func (c *apiClient) doLegacyConnectorRequest(req *http.Request) (*gk.Connector, error) {
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	connector := legacyConnector{}
+	err = json.Unmarshal(body, &connector)
+	if err != nil {
+		return nil, err
+	}
+
+	return connector.toConnector(), nil
+}
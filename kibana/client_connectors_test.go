@@ -0,0 +1,166 @@
+package kibana
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gk "github.com/renato0307/go-kibana/kibana"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetConnector_legacy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/s/testSpace/api/actions/action/c55b6eb0", r.URL.Path)
+		fmt.Fprint(w, `{
+			"id": "c55b6eb0",
+			"actionTypeId": ".index",
+			"name": "my-connector",
+			"config": {"index": "test-index"},
+			"isPreconfigured": false
+		}`)
+	}))
+	defer ts.Close()
+
+	c := testClient(t, ts)
+	c.version = "7.12.1"
+
+	connector, err := c.GetConnector("c55b6eb0")
+	if assert.NoError(t, err) {
+		assert.Equal(t, ".index", connector.ConnectorTypeId)
+		assert.Equal(t, "my-connector", connector.Name)
+		assert.Equal(t, "test-index", connector.Config["index"])
+	}
+}
+
+func TestCreateConnector_legacy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/s/testSpace/api/actions/action", r.URL.Path)
+
+		body, _ := ioutil.ReadAll(r.Body)
+		payload := map[string]interface{}{}
+		_ = json.Unmarshal(body, &payload)
+		assert.Equal(t, ".slack", payload["actionTypeId"])
+		assert.NotContains(t, payload, "connector_type_id")
+
+		fmt.Fprint(w, `{"id": "c55b6eb0", "actionTypeId": ".slack", "name": "my-connector"}`)
+	}))
+	defer ts.Close()
+
+	c := testClient(t, ts)
+	c.version = "7.11.0"
+
+	connector, err := c.CreateConnector(gk.CreateConnector{
+		Name:            "my-connector",
+		ConnectorTypeId: ".slack",
+		Secrets:         map[string]interface{}{"webhookUrl": "https://hooks.slack.com"},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "c55b6eb0", connector.ID)
+	}
+}
+
+func TestGetConnector_current(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/s/testSpace/api/actions/connector/c55b6eb0", r.URL.Path)
+		fmt.Fprint(w, `{"id": "c55b6eb0", "connector_type_id": ".index", "name": "my-connector"}`)
+	}))
+	defer ts.Close()
+
+	c := testClient(t, ts)
+	c.version = "7.13.0"
+
+	connector, err := c.GetConnector("c55b6eb0")
+	if assert.NoError(t, err) {
+		assert.Equal(t, ".index", connector.ConnectorTypeId)
+	}
+}
+
+func TestGetConnectorTypes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/s/testSpace/api/actions/connector_types":
+			fmt.Fprint(w, `[{
+				"id": ".slack",
+				"name": "Slack",
+				"enabled": true,
+				"enabled_in_config": true,
+				"enabled_in_license": true,
+				"minimum_license_required": "gold",
+				"supported_feature_ids": ["alerting"]
+			}]`)
+		case "/s/testSpace/api/actions/list_action_types":
+			fmt.Fprint(w, `[{
+				"id": ".slack",
+				"name": "Slack",
+				"enabled": true,
+				"enabledInConfig": true,
+				"enabledInLicense": false,
+				"minimumLicenseRequired": "gold"
+			}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c := testClient(t, ts)
+
+	c.version = "7.13.0"
+	types, err := c.getConnectorTypes()
+	if assert.NoError(t, err) && assert.Len(t, types, 1) {
+		assert.Equal(t, ".slack", types[0].ID)
+		assert.True(t, types[0].EnabledInLicense)
+		assert.Equal(t, "gold", types[0].MinimumLicenseRequired)
+		assert.Equal(t, []string{"alerting"}, types[0].SupportedFeatureIDs)
+	}
+
+	c.version = "7.12.1"
+	types, err = c.getConnectorTypes()
+	if assert.NoError(t, err) && assert.Len(t, types, 1) {
+		assert.Equal(t, ".slack", types[0].ID)
+		assert.True(t, types[0].EnabledInConfig)
+		assert.False(t, types[0].EnabledInLicense)
+		assert.Equal(t, "gold", types[0].MinimumLicenseRequired)
+	}
+}
+
+func TestGetConnectors_legacy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/s/testSpace/api/actions", r.URL.Path)
+		fmt.Fprint(w, `[{"id": "my-slack", "name": "ops", "actionTypeId": ".slack", "isPreconfigured": true}]`)
+	}))
+	defer ts.Close()
+
+	c := testClient(t, ts)
+	c.version = "7.12.1"
+
+	connectors, err := c.getConnectors()
+	if assert.NoError(t, err) && assert.Len(t, connectors, 1) {
+		assert.Equal(t, ".slack", connectors[0].ConnectorTypeID)
+		assert.True(t, connectors[0].IsPreconfigured)
+	}
+}
+
+func TestExecuteConnector_legacy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/s/testSpace/api/actions/action/c55b6eb0/_execute", r.URL.Path)
+		fmt.Fprint(w, `{"actionId": "c55b6eb0", "status": "ok", "data": {"items": []}}`)
+	}))
+	defer ts.Close()
+
+	c := testClient(t, ts)
+	c.version = "7.12.1"
+
+	execution, err := c.executeConnector("c55b6eb0", map[string]interface{}{"documents": []interface{}{}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "c55b6eb0", execution.ConnectorID)
+		assert.Equal(t, "ok", execution.Status)
+		assert.Equal(t, map[string]interface{}{"items": []interface{}{}}, execution.Data)
+	}
+}
@@ -0,0 +1,97 @@
+package kibana
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// maintenanceWindow - period when the rules of a space do not run actions
+type maintenanceWindow struct {
+	ID             string                   `json:"id"`
+	Title          string                   `json:"title"`
+	Enabled        bool                     `json:"enabled"`
+	Duration       int64                    `json:"duration"`
+	RRule          rRule                    `json:"r_rule"`
+	Status         string                   `json:"status"`
+	Events         []maintenanceWindowEvent `json:"events"`
+	ExpirationDate string                   `json:"expiration_date"`
+}
+
+// maintenanceWindowEvent - occurrence of a maintenance window
+type maintenanceWindowEvent struct {
+	Gte string `json:"gte"`
+	Lte string `json:"lte"`
+}
+
+// maintenanceWindowCreate - attributes used to create a maintenance window
+type maintenanceWindowCreate struct {
+	Title    string `json:"title"`
+	Duration int64  `json:"duration"`
+	RRule    rRule  `json:"r_rule"`
+}
+
+// maintenanceWindowUpdate - attributes used to update a maintenance window
+type maintenanceWindowUpdate struct {
+	Title    string `json:"title"`
+	Enabled  bool   `json:"enabled"`
+	Duration int64  `json:"duration"`
+	RRule    rRule  `json:"r_rule"`
+}
+
+// maintenanceWindowURL - returns the URL of the maintenance window API, for a
+// maintenance window when windowID is set
+//
+// Kibana only offers maintenance windows as an internal API.
+func (c *apiClient) maintenanceWindowURL(windowID string) string {
+	url := fmt.Sprintf("%s/s/%s/internal/alerting/rules/maintenance_window", c.HostURL, c.Space)
+	if windowID != "" {
+		url += "/" + windowID
+	}
+	return url
+}
+
+// getMaintenanceWindow - Retrieves a maintenance window by ID.
+func (c *apiClient) getMaintenanceWindow(windowID string) (*maintenanceWindow, error) {
+	body, err := c.doInternalRequest("GET", c.maintenanceWindowURL(windowID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalMaintenanceWindow(body)
+}
+
+// createMaintenanceWindow - Creates a maintenance window, enabled.
+func (c *apiClient) createMaintenanceWindow(w maintenanceWindowCreate) (*maintenanceWindow, error) {
+	body, err := c.doInternalRequest("POST", c.maintenanceWindowURL(""), w)
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalMaintenanceWindow(body)
+}
+
+// updateMaintenanceWindow - Updates an existing maintenance window.
+func (c *apiClient) updateMaintenanceWindow(windowID string, w maintenanceWindowUpdate) (*maintenanceWindow, error) {
+	body, err := c.doInternalRequest("POST", c.maintenanceWindowURL(windowID), w)
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalMaintenanceWindow(body)
+}
+
+// deleteMaintenanceWindow - Deletes a maintenance window by ID.
+func (c *apiClient) deleteMaintenanceWindow(windowID string) error {
+	_, err := c.doInternalRequest("DELETE", c.maintenanceWindowURL(windowID), nil)
+	return err
+}
+
+func unmarshalMaintenanceWindow(body []byte) (*maintenanceWindow, error) {
+	w := maintenanceWindow{}
+	err := json.Unmarshal(body, &w)
+	if err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
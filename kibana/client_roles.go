@@ -0,0 +1,107 @@
+package kibana
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// role - Kibana role with Elasticsearch and Kibana privileges
+type role struct {
+	Name          string                 `json:"name,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	Elasticsearch roleElasticsearch      `json:"elasticsearch"`
+	Kibana        []roleKibana           `json:"kibana"`
+}
+
+type roleElasticsearch struct {
+	Cluster []string      `json:"cluster"`
+	Indices []roleIndices `json:"indices"`
+	RunAs   []string      `json:"run_as"`
+}
+
+type roleIndices struct {
+	Names                  []string           `json:"names"`
+	Privileges             []string           `json:"privileges"`
+	FieldSecurity          *roleFieldSecurity `json:"field_security,omitempty"`
+	Query                  json.RawMessage    `json:"query,omitempty"`
+	AllowRestrictedIndices bool               `json:"allow_restricted_indices,omitempty"`
+}
+
+type roleFieldSecurity struct {
+	Grant  []string `json:"grant,omitempty"`
+	Except []string `json:"except,omitempty"`
+}
+
+type roleKibana struct {
+	Base    []string            `json:"base"`
+	Feature map[string][]string `json:"feature"`
+	Spaces  []string            `json:"spaces"`
+}
+
+// getRole - Retrieves a role by name.
+// Check https://www.elastic.co/guide/en/kibana/7.13/role-management-specific-api-get.html
+func (c *apiClient) getRole(name string) (*role, error) {
+	url := fmt.Sprintf("%s/api/security/role/%s", c.HostURL, name)
+	log.Printf("Getting role using %s", url)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r := role{}
+	err = json.Unmarshal(body, &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// putRole - Creates or updates a role.
+// Check https://www.elastic.co/guide/en/kibana/7.13/role-management-api-put.html
+func (c *apiClient) putRole(r role) error {
+	url := fmt.Sprintf("%s/api/security/role/%s", c.HostURL, r.Name)
+	log.Printf("Putting role using %s", url)
+
+	// the role name is part of the URL and not accepted in the body
+	r.Name = ""
+	rb, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("PUT", url, strings.NewReader(string(rb)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("kbn-xsrf", "true")
+	req.Header.Set("content-type", "application/json")
+
+	_, err = c.doRequest(req)
+	return err
+}
+
+// deleteRole - Deletes a role by name.
+// Check https://www.elastic.co/guide/en/kibana/7.13/role-management-api-delete.html
+func (c *apiClient) deleteRole(name string) error {
+	url := fmt.Sprintf("%s/api/security/role/%s", c.HostURL, name)
+	log.Printf("Deleting role using %s", url)
+
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("kbn-xsrf", "true")
+
+	_, err = c.doRequest(req)
+	return err
+}
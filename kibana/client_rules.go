@@ -0,0 +1,490 @@
+package kibana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// rule - alerting rule
+//
+// The params of a rule depend on its rule type, so they are kept as a map
+// instead of the fixed go-kibana RuleParams struct.
+type rule struct {
+	Actions         []ruleAction           `json:"actions"`
+	ApiKeyOwner     string                 `json:"api_key_owner"`
+	Consumer        string                 `json:"consumer"`
+	CreatedAt       string                 `json:"created_at"`
+	CreatedBy       string                 `json:"created_by"`
+	Enabled         bool                   `json:"enabled"`
+	ExecutionStatus ruleExecutionStatus    `json:"execution_status"`
+	ID              string                 `json:"id"`
+	IsSnoozedUntil  string                 `json:"is_snoozed_until"`
+	Monitoring      *ruleMonitoring        `json:"monitoring"`
+	MuteAll         bool                   `json:"mute_all"`
+	MutedAlertIDs   []string               `json:"muted_alert_ids"`
+	Name            string                 `json:"name"`
+	NotifyWhen      string                 `json:"notify_when"`
+	Params          map[string]interface{} `json:"params"`
+	RuleTypeID      string                 `json:"rule_type_id"`
+	Schedule        ruleSchedule           `json:"schedule"`
+	ScheduledTaskID string                 `json:"scheduled_task_id"`
+	SnoozeSchedule  []ruleSnoozeSchedule   `json:"snooze_schedule"`
+	Tags            []string               `json:"tags"`
+	Throttle        string                 `json:"throttle"`
+	UpdatedAt       string                 `json:"updated_at"`
+	UpdatedBy       string                 `json:"updated_by"`
+}
+
+// ruleAction - action run by a rule
+type ruleAction struct {
+	ID        string                 `json:"id"`
+	Group     string                 `json:"group"`
+	Params    map[string]interface{} `json:"params"`
+	Frequency *ruleActionFrequency   `json:"frequency,omitempty"`
+}
+
+// ruleActionFrequency - how often an action runs, replacing the notify_when
+// and throttle of the rule
+type ruleActionFrequency struct {
+	Summary    bool    `json:"summary"`
+	NotifyWhen string  `json:"notify_when"`
+	Throttle   *string `json:"throttle"`
+}
+
+// ruleSchedule - check interval of a rule
+type ruleSchedule struct {
+	Interval string `json:"interval"`
+}
+
+// ruleSnoozeSchedule - period when the actions of a rule do not run
+type ruleSnoozeSchedule struct {
+	ID       string `json:"id,omitempty"`
+	Duration int64  `json:"duration"`
+	RRule    rRule  `json:"rRule"`
+}
+
+// ruleExecutionStatus - result of the last run of a rule
+type ruleExecutionStatus struct {
+	Error             *ruleExecutionMessage `json:"error"`
+	LastDuration      int64                 `json:"last_duration"`
+	LastExecutionDate string                `json:"last_execution_date"`
+	Status            string                `json:"status"`
+	Warning           *ruleExecutionMessage `json:"warning"`
+}
+
+// ruleExecutionMessage - reason and description of an error or warning of a
+// rule run
+type ruleExecutionMessage struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// ruleMonitoring - health of the recent runs of a rule
+type ruleMonitoring struct {
+	Run struct {
+		History           []ruleRun `json:"history"`
+		CalculatedMetrics struct {
+			SuccessRatio float64 `json:"success_ratio"`
+		} `json:"calculated_metrics"`
+	} `json:"run"`
+}
+
+// ruleRun - recent run of a rule, with its timestamp and duration in
+// milliseconds
+type ruleRun struct {
+	Duration  int64  `json:"duration"`
+	Outcome   string `json:"outcome"`
+	Success   bool   `json:"success"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// ruleCreate - attributes used to create a rule
+type ruleCreate struct {
+	Actions    []ruleAction           `json:"actions"`
+	Consumer   string                 `json:"consumer"`
+	Enabled    bool                   `json:"enabled"`
+	Name       string                 `json:"name"`
+	NotifyWhen string                 `json:"notify_when,omitempty"`
+	Params     map[string]interface{} `json:"params"`
+	RuleTypeID string                 `json:"rule_type_id"`
+	Schedule   ruleSchedule           `json:"schedule"`
+	Tags       []string               `json:"tags"`
+	Throttle   string                 `json:"throttle,omitempty"`
+}
+
+// ruleUpdate - attributes used to update a rule
+type ruleUpdate struct {
+	Actions    []ruleAction           `json:"actions"`
+	Name       string                 `json:"name"`
+	NotifyWhen string                 `json:"notify_when,omitempty"`
+	Params     map[string]interface{} `json:"params"`
+	Schedule   ruleSchedule           `json:"schedule"`
+	Tags       []string               `json:"tags"`
+	Throttle   string                 `json:"throttle,omitempty"`
+}
+
+// legacyRule - rule as used by the /api/alerts/alert API of Kibana versions
+// older than 7.13
+type legacyRule struct {
+	Actions          []ruleAction               `json:"actions"`
+	AlertTypeID      string                     `json:"alertTypeId,omitempty"`
+	ApiKeyOwner      string                     `json:"apiKeyOwner,omitempty"`
+	Consumer         string                     `json:"consumer,omitempty"`
+	CreatedAt        string                     `json:"createdAt,omitempty"`
+	CreatedBy        string                     `json:"createdBy,omitempty"`
+	Enabled          bool                       `json:"enabled,omitempty"`
+	ExecutionStatus  *legacyRuleExecutionStatus `json:"executionStatus,omitempty"`
+	ID               string                     `json:"id,omitempty"`
+	MuteAll          bool                       `json:"muteAll,omitempty"`
+	MutedInstanceIDs []string                   `json:"mutedInstanceIds,omitempty"`
+	Name             string                     `json:"name"`
+	NotifyWhen       string                     `json:"notifyWhen,omitempty"`
+	Params           map[string]interface{}     `json:"params"`
+	Schedule         ruleSchedule               `json:"schedule"`
+	ScheduledTaskID  string                     `json:"scheduledTaskId,omitempty"`
+	Tags             []string                   `json:"tags"`
+	Throttle         string                     `json:"throttle,omitempty"`
+	UpdatedAt        string                     `json:"updatedAt,omitempty"`
+	UpdatedBy        string                     `json:"updatedBy,omitempty"`
+}
+
+// legacyRuleCreate - attributes used to create a rule with the legacy API,
+// always sending enabled as rules are otherwise created enabled
+type legacyRuleCreate struct {
+	legacyRule
+	Enabled bool `json:"enabled"`
+}
+
+type legacyRuleExecutionStatus struct {
+	Error             *ruleExecutionMessage `json:"error"`
+	LastExecutionDate string                `json:"lastExecutionDate"`
+	Status            string                `json:"status"`
+}
+
+func (l legacyRule) toRule() *rule {
+	r := &rule{
+		Actions:         l.Actions,
+		ApiKeyOwner:     l.ApiKeyOwner,
+		Consumer:        l.Consumer,
+		CreatedAt:       l.CreatedAt,
+		CreatedBy:       l.CreatedBy,
+		Enabled:         l.Enabled,
+		ID:              l.ID,
+		MuteAll:         l.MuteAll,
+		MutedAlertIDs:   l.MutedInstanceIDs,
+		Name:            l.Name,
+		NotifyWhen:      l.NotifyWhen,
+		Params:          l.Params,
+		RuleTypeID:      l.AlertTypeID,
+		Schedule:        l.Schedule,
+		ScheduledTaskID: l.ScheduledTaskID,
+		Tags:            l.Tags,
+		Throttle:        l.Throttle,
+		UpdatedAt:       l.UpdatedAt,
+		UpdatedBy:       l.UpdatedBy,
+	}
+	if l.ExecutionStatus != nil {
+		r.ExecutionStatus = ruleExecutionStatus{
+			Error:             l.ExecutionStatus.Error,
+			LastExecutionDate: l.ExecutionStatus.LastExecutionDate,
+			Status:            l.ExecutionStatus.Status,
+		}
+	}
+	return r
+}
+
+// ruleURL - returns the URL of the rule API of the server version, for a rule
+// when ruleID is set
+func (c *apiClient) ruleURL(ruleID string) string {
+	path := "api/alerting/rule"
+	if !c.supports(versionAlertingAPI) {
+		path = "api/alerts/alert"
+	}
+
+	url := fmt.Sprintf("%s/s/%s/%s", c.HostURL, c.Space, path)
+	if ruleID != "" {
+		url += "/" + ruleID
+	}
+	return url
+}
+
+// GetRule - Retrieves a rule by ID using the API of the server version.
+// Check https://www.elastic.co/guide/en/kibana/7.13/get-rule-api.html
+func (c *apiClient) GetRule(ruleID string) (*rule, error) {
+	url := c.ruleURL(ruleID)
+	log.Printf("Getting rule using %s", url)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doRuleRequest(req)
+}
+
+// CreateRule - Creates a rule using the API of the server version.
+// Check https://www.elastic.co/guide/en/kibana/7.13/create-rule-api.html
+func (c *apiClient) CreateRule(r ruleCreate) (*rule, error) {
+	url := c.ruleURL("")
+	log.Printf("Creating rule using %s", url)
+
+	var payload interface{} = r
+	if !c.supports(versionAlertingAPI) {
+		payload = legacyRuleCreate{
+			legacyRule: legacyRule{
+				Actions:     r.Actions,
+				AlertTypeID: r.RuleTypeID,
+				Consumer:    r.Consumer,
+				Name:        r.Name,
+				NotifyWhen:  r.NotifyWhen,
+				Params:      r.Params,
+				Schedule:    r.Schedule,
+				Tags:        r.Tags,
+				Throttle:    r.Throttle,
+			},
+			Enabled: r.Enabled,
+		}
+	}
+
+	rb, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("kbn-xsrf", "true")
+	req.Header.Set("content-type", "application/json")
+
+	return c.doRuleRequest(req)
+}
+
+// UpdateRule - Updates an existing rule using the API of the server version.
+// Check https://www.elastic.co/guide/en/kibana/7.13/update-rule-api.html
+func (c *apiClient) UpdateRule(ruleID string, r ruleUpdate) (*rule, error) {
+	url := c.ruleURL(ruleID)
+	log.Printf("Updating rule using %s", url)
+
+	var payload interface{} = r
+	if !c.supports(versionAlertingAPI) {
+		payload = legacyRule{
+			Actions:    r.Actions,
+			Name:       r.Name,
+			NotifyWhen: r.NotifyWhen,
+			Params:     r.Params,
+			Schedule:   r.Schedule,
+			Tags:       r.Tags,
+			Throttle:   r.Throttle,
+		}
+	}
+
+	rb, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", url, strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("kbn-xsrf", "true")
+	req.Header.Set("content-type", "application/json")
+
+	return c.doRuleRequest(req)
+}
+
+// DeleteRule - Deletes a rule by ID using the API of the server version.
+// Check https://www.elastic.co/guide/en/kibana/7.13/delete-rule-api.html
+func (c *apiClient) DeleteRule(ruleID string) error {
+	url := c.ruleURL(ruleID)
+	log.Printf("Deleting rule using %s", url)
+
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("kbn-xsrf", "true")
+
+	_, err = c.doRequest(req)
+	return err
+}
+
+// enableRule - Enables a rule, scheduling its checks.
+// Check https://www.elastic.co/guide/en/kibana/7.13/enable-rule-api.html
+func (c *apiClient) enableRule(ruleID string) error {
+	return c.doRuleActionRequest(c.ruleURL(ruleID) + "/_enable")
+}
+
+// disableRule - Disables a rule, stopping its checks.
+// Check https://www.elastic.co/guide/en/kibana/7.13/disable-rule-api.html
+func (c *apiClient) disableRule(ruleID string) error {
+	return c.doRuleActionRequest(c.ruleURL(ruleID) + "/_disable")
+}
+
+// updateRuleAPIKey - Replaces the API key of a rule with one for the user of
+// the client.
+// Check https://www.elastic.co/guide/en/kibana/7.13/update-api-key-rule-api.html
+func (c *apiClient) updateRuleAPIKey(ruleID string) error {
+	return c.doRuleActionRequest(c.ruleURL(ruleID) + "/_update_api_key")
+}
+
+// muteAllRule - Mutes all alerts of a rule.
+// Check https://www.elastic.co/guide/en/kibana/7.13/mute-all-alerts-api.html
+func (c *apiClient) muteAllRule(ruleID string) error {
+	return c.doRuleActionRequest(c.ruleURL(ruleID) + "/_mute_all")
+}
+
+// unmuteAllRule - Unmutes all alerts of a rule.
+// Check https://www.elastic.co/guide/en/kibana/7.13/unmute-all-alerts-api.html
+func (c *apiClient) unmuteAllRule(ruleID string) error {
+	return c.doRuleActionRequest(c.ruleURL(ruleID) + "/_unmute_all")
+}
+
+// muteAlert - Mutes an alert of a rule.
+// Check https://www.elastic.co/guide/en/kibana/7.13/mute-alert-api.html
+func (c *apiClient) muteAlert(ruleID, alertID string) error {
+	return c.doRuleActionRequest(c.alertURL(ruleID, alertID) + "/_mute")
+}
+
+// unmuteAlert - Unmutes an alert of a rule.
+// Check https://www.elastic.co/guide/en/kibana/7.13/unmute-alert-api.html
+func (c *apiClient) unmuteAlert(ruleID, alertID string) error {
+	return c.doRuleActionRequest(c.alertURL(ruleID, alertID) + "/_unmute")
+}
+
+// runRuleSoon - Schedules a run of a rule as soon as possible.
+//
+// Kibana only offers this as an internal API.
+func (c *apiClient) runRuleSoon(ruleID string) error {
+	url := fmt.Sprintf("%s/s/%s/internal/alerting/rule/%s/_run_soon", c.HostURL, c.Space, ruleID)
+	_, err := c.doInternalRequest("POST", url, nil)
+	return err
+}
+
+// snoozeRule - Adds a snooze schedule to a rule.
+//
+// Kibana only offers this as an internal API.
+func (c *apiClient) snoozeRule(ruleID string, s ruleSnoozeSchedule) error {
+	url := fmt.Sprintf("%s/s/%s/internal/alerting/rule/%s/_snooze", c.HostURL, c.Space, ruleID)
+	_, err := c.doInternalRequest("POST", url, map[string]interface{}{"snooze_schedule": s})
+	return err
+}
+
+// unsnoozeRule - Removes snooze schedules from a rule.
+//
+// Kibana only offers this as an internal API.
+func (c *apiClient) unsnoozeRule(ruleID string, scheduleIDs []string) error {
+	url := fmt.Sprintf("%s/s/%s/internal/alerting/rule/%s/_unsnooze", c.HostURL, c.Space, ruleID)
+	_, err := c.doInternalRequest("POST", url, map[string]interface{}{"schedule_ids": scheduleIDs})
+	return err
+}
+
+// alertURL - returns the URL of an alert of a rule, using the API of the
+// server version
+func (c *apiClient) alertURL(ruleID, alertID string) string {
+	if !c.supports(versionAlertingAPI) {
+		return fmt.Sprintf("%s/alert_instance/%s", c.ruleURL(ruleID), url.PathEscape(alertID))
+	}
+	return fmt.Sprintf("%s/alert/%s", c.ruleURL(ruleID), url.PathEscape(alertID))
+}
+
+// doRuleActionRequest - calls an endpoint that changes the state of a rule
+// and returns no content
+func (c *apiClient) doRuleActionRequest(url string) error {
+	log.Printf("Calling %s", url)
+
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("kbn-xsrf", "true")
+
+	_, err = c.doRequest(req)
+	return err
+}
+
+// doInternalRequest - calls an internal Kibana API, which must be flagged as
+// coming from Kibana itself
+//
+// Internal APIs are not documented and can change in any Kibana release, so
+// an endpoint missing from the server gets an explicit error.
+func (c *apiClient) doInternalRequest(method string, url string, payload interface{}) ([]byte, error) {
+	log.Printf("Calling %s", url)
+
+	body := ""
+	if payload != nil {
+		rb, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = string(rb)
+	}
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("kbn-xsrf", "true")
+	req.Header.Set("x-elastic-internal-origin", "Kibana")
+	if payload != nil {
+		req.Header.Set("content-type", "application/json")
+	}
+
+	res, err := c.doRequest(req)
+	if isMissingEndpoint(err) {
+		version := c.version
+		if version == "" {
+			version = "unknown"
+		}
+		return nil, fmt.Errorf("the Kibana server (version %s) does not offer the internal API %s %s, which this provider "+
+			"relies on as Kibana has no public API for it; check the supported Kibana versions in the provider documentation",
+			version, method, req.URL.Path)
+	}
+	return res, err
+}
+
+// isMissingEndpoint - checks if an error is the 404 response Kibana returns
+// for an unknown route, unlike the 404 of a missing object, whose message
+// names the object
+func isMissingEndpoint(err error) bool {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		return false
+	}
+
+	var response struct {
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(apiErr.Body, &response)
+	return response.Message == "Not Found"
+}
+
+func (c *apiClient) doRuleRequest(req *http.Request) (*rule, error) {
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.supports(versionAlertingAPI) {
+		r := rule{}
+		err = json.Unmarshal(body, &r)
+		if err != nil {
+			return nil, err
+		}
+		return &r, nil
+	}
+
+	l := legacyRule{}
+	err = json.Unmarshal(body, &l)
+	if err != nil {
+		return nil, err
+	}
+	return l.toRule(), nil
+}
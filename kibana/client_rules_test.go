@@ -0,0 +1,181 @@
+package kibana
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRule_legacy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/s/testSpace/api/alerts/alert/0a037d60", r.URL.Path)
+		fmt.Fprint(w, `{
+			"id": "0a037d60",
+			"notifyWhen": "onActionGroupChange",
+			"params": {"aggType": "avg", "timeWindowSize": 5},
+			"consumer": "alerts",
+			"alertTypeId": ".index-threshold",
+			"schedule": {"interval": "1m"},
+			"actions": [],
+			"tags": ["tag1"],
+			"name": "test rule",
+			"enabled": true,
+			"apiKeyOwner": "elastic",
+			"mutedInstanceIds": ["asdfgh"],
+			"scheduledTaskId": "0b092d90",
+			"executionStatus": {"lastExecutionDate": "2021-02-10T17:55:14.262Z", "status": "ok"}
+		}`)
+	}))
+	defer ts.Close()
+
+	c := testClient(t, ts)
+	c.version = "7.12.0"
+
+	rule, err := c.GetRule("0a037d60")
+	if assert.NoError(t, err) {
+		assert.Equal(t, ".index-threshold", rule.RuleTypeID)
+		assert.Equal(t, "onActionGroupChange", rule.NotifyWhen)
+		assert.Equal(t, "elastic", rule.ApiKeyOwner)
+		assert.Equal(t, []string{"asdfgh"}, rule.MutedAlertIDs)
+		assert.Equal(t, "ok", rule.ExecutionStatus.Status)
+		assert.Equal(t, float64(5), rule.Params["timeWindowSize"])
+	}
+}
+
+func TestCreateRule_legacy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/s/testSpace/api/alerts/alert", r.URL.Path)
+
+		body, _ := ioutil.ReadAll(r.Body)
+		payload := map[string]interface{}{}
+		_ = json.Unmarshal(body, &payload)
+		assert.Equal(t, ".index-threshold", payload["alertTypeId"])
+		assert.Equal(t, "onActiveAlert", payload["notifyWhen"])
+		assert.Equal(t, "10m", payload["throttle"])
+		assert.Equal(t, false, payload["enabled"])
+		assert.NotContains(t, payload, "rule_type_id")
+		assert.NotContains(t, payload, "executionStatus")
+		assert.Equal(t, map[string]interface{}{"aggType": "avg"}, payload["params"])
+
+		fmt.Fprint(w, `{"id": "0a037d60", "alertTypeId": ".index-threshold", "name": "test rule"}`)
+	}))
+	defer ts.Close()
+
+	c := testClient(t, ts)
+	c.version = "7.11.2"
+
+	rule, err := c.CreateRule(ruleCreate{
+		Consumer:   "alerts",
+		Name:       "test rule",
+		NotifyWhen: "onActiveAlert",
+		Params:     map[string]interface{}{"aggType": "avg"},
+		RuleTypeID: ".index-threshold",
+		Schedule:   ruleSchedule{Interval: "1m"},
+		Throttle:   "10m",
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0a037d60", rule.ID)
+	}
+}
+
+func TestMuteAlert_legacy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "true", r.Header.Get("kbn-xsrf"))
+		assert.Equal(t, "/s/testSpace/api/alerts/alert/0a037d60/alert_instance/host-1/_mute", r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c := testClient(t, ts)
+	c.version = "7.12.0"
+
+	assert.NoError(t, c.muteAlert("0a037d60", "host-1"))
+}
+
+func TestCreateRule_legacyWithoutNotifyWhen(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		payload := map[string]interface{}{}
+		_ = json.Unmarshal(body, &payload)
+		assert.NotContains(t, payload, "notifyWhen")
+
+		fmt.Fprint(w, `{"id": "0a037d60", "alertTypeId": ".index-threshold", "name": "test rule"}`)
+	}))
+	defer ts.Close()
+
+	c := testClient(t, ts)
+	c.version = "7.10.2"
+
+	_, err := c.CreateRule(ruleCreate{
+		Consumer:   "alerts",
+		Name:       "test rule",
+		Params:     map[string]interface{}{},
+		RuleTypeID: ".index-threshold",
+		Schedule:   ruleSchedule{Interval: "1m"},
+	})
+	assert.NoError(t, err)
+}
+
+func TestSnoozeRule(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/s/testSpace/internal/alerting/rule/0a037d60/_snooze", r.URL.Path)
+		assert.Equal(t, "Kibana", r.Header.Get("x-elastic-internal-origin"))
+
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{"snooze_schedule": {
+			"duration": 3600000,
+			"rRule": {"dtstart": "2024-05-04T22:00:00Z", "tzid": "UTC", "freq": 2, "byweekday": ["SA"]}
+		}}`, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c := testClient(t, ts)
+
+	freq := 2
+	err := c.snoozeRule("0a037d60", ruleSnoozeSchedule{
+		Duration: 3600000,
+		RRule: rRule{
+			Dtstart:   "2024-05-04T22:00:00Z",
+			Tzid:      "UTC",
+			Freq:      &freq,
+			Byweekday: []interface{}{"SA"},
+		},
+	})
+	assert.NoError(t, err)
+}
+
+func TestDoInternalRequest_missingEndpoint(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		if strings.HasSuffix(r.URL.Path, "/_run_soon") {
+			fmt.Fprint(w, `{"statusCode": 404, "error": "Not Found", "message": "Not Found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"statusCode": 404, "error": "Not Found", "message": "Saved object [maintenance-window/5f2b] not found"}`)
+	}))
+	defer ts.Close()
+
+	c := testClient(t, ts)
+	c.version = "8.1.3"
+
+	err := c.runRuleSoon("0a037d60")
+	if assert.Error(t, err) {
+		assert.False(t, isNotFound(err))
+		assert.Equal(t, "the Kibana server (version 8.1.3) does not offer the internal API POST /s/testSpace/internal/alerting/rule/0a037d60/_run_soon, "+
+			"which this provider relies on as Kibana has no public API for it; check the supported Kibana versions in the provider documentation", err.Error())
+	}
+
+	// a missing object is still reported as not found
+	_, err = c.getMaintenanceWindow("5f2b")
+	assert.True(t, isNotFound(err))
+}
@@ -0,0 +1,107 @@
+package kibana
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// space - Kibana space
+type space struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	Description      string   `json:"description,omitempty"`
+	Color            string   `json:"color,omitempty"`
+	Initials         string   `json:"initials,omitempty"`
+	ImageURL         string   `json:"imageUrl,omitempty"`
+	DisabledFeatures []string `json:"disabledFeatures"`
+}
+
+// getSpace - Retrieves a space by ID.
+// Check https://www.elastic.co/guide/en/kibana/7.13/spaces-api-get.html
+func (c *apiClient) getSpace(spaceID string) (*space, error) {
+	url := fmt.Sprintf("%s/api/spaces/space/%s", c.HostURL, spaceID)
+	log.Printf("Getting space using %s", url)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doSpaceRequest(req)
+}
+
+// createSpace - Creates a space.
+// Check https://www.elastic.co/guide/en/kibana/7.13/spaces-api-post.html
+func (c *apiClient) createSpace(s space) (*space, error) {
+	url := fmt.Sprintf("%s/api/spaces/space", c.HostURL)
+	log.Printf("Creating space using %s", url)
+
+	rb, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("kbn-xsrf", "true")
+	req.Header.Set("content-type", "application/json")
+
+	return c.doSpaceRequest(req)
+}
+
+// updateSpace - Updates an existing space.
+// Check https://www.elastic.co/guide/en/kibana/7.13/spaces-api-put.html
+func (c *apiClient) updateSpace(s space) (*space, error) {
+	url := fmt.Sprintf("%s/api/spaces/space/%s", c.HostURL, s.ID)
+	log.Printf("Updating space using %s", url)
+
+	rb, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", url, strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("kbn-xsrf", "true")
+	req.Header.Set("content-type", "application/json")
+
+	return c.doSpaceRequest(req)
+}
+
+// deleteSpace - Deletes a space and all the saved objects it contains.
+// Check https://www.elastic.co/guide/en/kibana/7.13/spaces-api-delete.html
+func (c *apiClient) deleteSpace(spaceID string) error {
+	url := fmt.Sprintf("%s/api/spaces/space/%s", c.HostURL, spaceID)
+	log.Printf("Deleting space using %s", url)
+
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("kbn-xsrf", "true")
+
+	_, err = c.doRequest(req)
+	return err
+}
+
+func (c *apiClient) doSpaceRequest(req *http.Request) (*space, error) {
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	s := space{}
+	err = json.Unmarshal(body, &s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
@@ -0,0 +1,91 @@
+package kibana
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// kibanaStatus - status of the Kibana server
+type kibanaStatus struct {
+	Name    string `json:"name"`
+	Version struct {
+		Number string `json:"number"`
+	} `json:"version"`
+}
+
+// getStatus - retrieves the Kibana server status.
+// Check https://www.elastic.co/guide/en/kibana/current/access.html
+func (c *apiClient) getStatus() (*kibanaStatus, error) {
+	url := fmt.Sprintf("%s/api/status", c.HostURL)
+	log.Printf("Getting status using %s", url)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	status := kibanaStatus{}
+	err = json.Unmarshal(body, &status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// checkConnection - checks that Kibana is reachable and stores its version
+func (c *apiClient) checkConnection() diag.Diagnostics {
+	status, err := c.getStatus()
+	if err != nil {
+		return diag.Diagnostics{connectionDiagnostic(c.HostURL, err)}
+	}
+
+	c.version = status.Version.Number
+	log.Printf("[INFO] Connected to Kibana %s version %s", status.Name, c.version)
+
+	return nil
+}
+
+// connectionDiagnostic - describes why Kibana could not be reached
+func connectionDiagnostic(host string, err error) diag.Diagnostic {
+	var dnsErr *net.DNSError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certificateInvalidErr x509.CertificateInvalidError
+	var recordHeaderErr tls.RecordHeaderError
+	var apiErr *apiError
+
+	summary := "Unable to connect to Kibana"
+	switch {
+	case errors.As(err, &dnsErr):
+		summary = "Unable to resolve the Kibana host"
+	case errors.As(err, &unknownAuthorityErr),
+		errors.As(err, &hostnameErr),
+		errors.As(err, &certificateInvalidErr),
+		errors.As(err, &recordHeaderErr):
+		summary = "Unable to establish a TLS connection to Kibana"
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized:
+		summary = "Kibana rejected the provider credentials (401)"
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden:
+		summary = "The provider credentials are not allowed to access Kibana (403)"
+	}
+
+	return diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  summary,
+		Detail:   fmt.Sprintf("Checking the Kibana status at %s failed: %s", host, err),
+	}
+}
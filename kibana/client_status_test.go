@@ -0,0 +1,55 @@
+package kibana
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckConnection(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/status", r.URL.Path)
+		fmt.Fprint(w, `{"name": "kibana", "version": {"number": "7.13.2"}}`)
+	}))
+	defer ts.Close()
+
+	c := testClient(t, ts)
+	diags := c.checkConnection()
+	assert.False(t, diags.HasError())
+	assert.Equal(t, "7.13.2", c.version)
+}
+
+func TestCheckConnection_errors(t *testing.T) {
+	cases := map[int]string{
+		http.StatusUnauthorized:        "Kibana rejected the provider credentials (401)",
+		http.StatusForbidden:           "The provider credentials are not allowed to access Kibana (403)",
+		http.StatusInternalServerError: "Unable to connect to Kibana",
+	}
+	for statusCode, summary := range cases {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(statusCode)
+		}))
+
+		diags := testClient(t, ts).checkConnection()
+		if assert.Len(t, diags, 1) {
+			assert.Equal(t, summary, diags[0].Summary)
+		}
+		ts.Close()
+	}
+}
+
+func TestCheckConnection_tls(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	c := testClient(t, ts)
+	c.HTTPClient = &http.Client{}
+
+	diags := c.checkConnection()
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "Unable to establish a TLS connection to Kibana", diags[0].Summary)
+	}
+}
@@ -0,0 +1,103 @@
+package kibana
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	gk "github.com/renato0307/go-kibana/kibana"
+	"github.com/stretchr/testify/assert"
+)
+
+// testClient - creates a client for the given test server
+func testClient(t *testing.T, ts *httptest.Server) *apiClient {
+	userName := "testUser"
+	password := "testPassword"
+	space := "testSpace"
+
+	c, err := gk.NewClient(&ts.URL, &userName, &password, &space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.HTTPClient = ts.Client()
+
+	return &apiClient{Client: c}
+}
+
+func TestDoRequest(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := r.BasicAuth()
+		if user != "testUser" || password != "testPassword" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		fmt.Fprint(w, `{}`)
+	}))
+	defer ts.Close()
+
+	c := testClient(t, ts)
+
+	req, _ := http.NewRequest("GET", ts.URL+"/found", nil)
+	body, err := c.doRequest(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", string(body))
+
+	req, _ = http.NewRequest("GET", ts.URL+"/missing", nil)
+	_, err = c.doRequest(req)
+	var apiErr *apiError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.Equal(t, "status: 404, body: {}", err.Error())
+	}
+}
+
+func TestWithSpace(t *testing.T) {
+	c := &apiClient{Client: &gk.Client{Space: "default"}, version: "8.6.0"}
+
+	assert.Same(t, c, c.withSpace(""))
+	assert.Same(t, c, c.withSpace("default"))
+
+	other := c.withSpace("team-a")
+	assert.Equal(t, "team-a", other.Space)
+	assert.Equal(t, "8.6.0", other.version)
+	assert.Equal(t, "default", c.Space)
+}
+
+func TestResourceImportSpaceObject(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceActionsConnector().Schema, map[string]interface{}{})
+
+	d.SetId("team-a/c55b6eb0")
+	_, err := resourceImportSpaceObject(context.Background(), d, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "c55b6eb0", d.Id())
+		assert.Equal(t, "team-a", d.Get("space_id"))
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceActionsConnector().Schema, map[string]interface{}{})
+	d.SetId("c55b6eb0")
+	_, err = resourceImportSpaceObject(context.Background(), d, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "c55b6eb0", d.Id())
+		assert.Equal(t, "", d.Get("space_id"))
+	}
+
+	for _, id := range []string{"/c55b6eb0", "team-a/", "team-a/c55b6eb0/x"} {
+		d.SetId(id)
+		_, err = resourceImportSpaceObject(context.Background(), d, nil)
+		assert.Error(t, err, id)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	assert.True(t, isNotFound(&apiError{StatusCode: http.StatusNotFound}))
+	assert.True(t, isNotFound(fmt.Errorf("status: 404, body: {}")))
+	assert.False(t, isNotFound(&apiError{StatusCode: http.StatusForbidden}))
+	assert.False(t, isNotFound(fmt.Errorf("status: 4040, body: {}")))
+	assert.False(t, isNotFound(nil))
+}
@@ -0,0 +1,48 @@
+package kibana
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// cloudIDKibanaURL - decodes an Elastic Cloud ID into the Kibana endpoint
+//
+// A Cloud ID has the format <name>:<base64 data>, where the data decodes to
+// <host>[:<port>]$<elasticsearch id>[:<port>]$<kibana id>[:<port>].
+func cloudIDKibanaURL(cloudID string) (string, error) {
+	parts := strings.SplitN(cloudID, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", fmt.Errorf("cloud ID must have the format <name>:<base64 data>")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+		if err != nil {
+			return "", fmt.Errorf("cloud ID data is not valid base64: %w", err)
+		}
+	}
+
+	segments := strings.Split(string(data), "$")
+	if len(segments) < 3 || segments[0] == "" || segments[2] == "" {
+		return "", fmt.Errorf("cloud ID does not contain a Kibana endpoint")
+	}
+
+	host, port := splitCloudIDPort(segments[0], "443")
+	kibanaID, port := splitCloudIDPort(segments[2], port)
+
+	if port == "443" {
+		return fmt.Sprintf("https://%s.%s", kibanaID, host), nil
+	}
+	return fmt.Sprintf("https://%s.%s:%s", kibanaID, host, port), nil
+}
+
+// splitCloudIDPort - splits an optional port from a Cloud ID segment
+func splitCloudIDPort(segment, defaultPort string) (string, string) {
+	i := strings.LastIndex(segment, ":")
+	if i < 0 {
+		return segment, defaultPort
+	}
+	return segment[:i], segment[i+1:]
+}
@@ -0,0 +1,44 @@
+package kibana
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudIDKibanaURL(t *testing.T) {
+	encode := func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	}
+
+	cases := []struct {
+		cloudID  string
+		expected string
+	}{
+		{"prod:" + encode("us-central1.gcp.cloud.es.io$es123$kb456"), "https://kb456.us-central1.gcp.cloud.es.io"},
+		{"prod:" + encode("us-central1.gcp.cloud.es.io:443$es123$kb456"), "https://kb456.us-central1.gcp.cloud.es.io"},
+		{"prod:" + encode("eu-west-1.aws.found.io:9243$es123$kb456"), "https://kb456.eu-west-1.aws.found.io:9243"},
+		{"prod:" + encode("eu-west-1.aws.found.io$es123:9243$kb456:9244"), "https://kb456.eu-west-1.aws.found.io:9244"},
+		{"prod:" + base64.RawStdEncoding.EncodeToString([]byte("host.io$es$kb1")), "https://kb1.host.io"},
+	}
+	for _, c := range cases {
+		actual, err := cloudIDKibanaURL(c.cloudID)
+		if assert.NoError(t, err, c.cloudID) {
+			assert.Equal(t, c.expected, actual)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"prod",
+		"prod:",
+		"prod:not-base64!",
+		"prod:" + encode("host.io$es123"),
+		"prod:" + encode("host.io$es123$"),
+	}
+	for _, cloudID := range invalid {
+		_, err := cloudIDKibanaURL(cloudID)
+		assert.Error(t, err, cloudID)
+	}
+}
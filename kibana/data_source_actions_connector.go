@@ -0,0 +1,131 @@
+package kibana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceActionsConnector() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceActionsConnectorRead,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"connector_type_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"space_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"is_preconfigured": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"is_deprecated": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"is_missing_secrets": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"config": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceActionsConnectorRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics // Warning or errors can be collected in a slice type
+	c := resourceClient(d, m)
+
+	connectors, err := c.getConnectors()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	connector, err := findConnector(connectors, d.Get("id").(string), d.Get("name").(string), d.Get("connector_type_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	config := ""
+	if connector.Config != nil {
+		configValue, err := json.Marshal(connector.Config)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		config = string(configValue)
+	}
+
+	d.SetId(connector.ID)
+	_ = d.Set("name", connector.Name)
+	_ = d.Set("connector_type_id", connector.ConnectorTypeID)
+	_ = d.Set("space_id", c.Space)
+	_ = d.Set("is_preconfigured", connector.IsPreconfigured)
+	_ = d.Set("is_deprecated", connector.IsDeprecated)
+	_ = d.Set("is_missing_secrets", connector.IsMissingSecrets)
+	_ = d.Set("config", config)
+
+	return diags
+}
+
+// findConnector - finds the only connector with an ID or name, optionally of
+// a connector type
+func findConnector(connectors []connectorInfo, id, name, connectorTypeID string) (*connectorInfo, error) {
+	var found []connectorInfo
+	for _, connector := range connectors {
+		if id != "" && connector.ID != id {
+			continue
+		}
+		if name != "" && connector.Name != name {
+			continue
+		}
+		if connectorTypeID != "" && connector.ConnectorTypeID != connectorTypeID {
+			continue
+		}
+		found = append(found, connector)
+	}
+
+	filter := fmt.Sprintf("id %q", id)
+	if id == "" {
+		filter = fmt.Sprintf("name %q", name)
+	}
+	if connectorTypeID != "" {
+		filter += fmt.Sprintf(" and type %q", connectorTypeID)
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no connector found with %s", filter)
+	case 1:
+		return &found[0], nil
+	default:
+		var ids []string
+		for _, connector := range found {
+			ids = append(ids, connector.ID)
+		}
+		return nil, fmt.Errorf("found %d connectors with %s (%s), set connector_type_id or id to select one",
+			len(found), filter, strings.Join(ids, ", "))
+	}
+}
@@ -0,0 +1,102 @@
+package kibana
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dataSourceActionsConnectorExecution - runs a connector on every read when
+// execute is set, so the result can be checked in preconditions and
+// postconditions
+func dataSourceActionsConnectorExecution() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceActionsConnectorExecutionRead,
+		Schema: map[string]*schema.Schema{
+			"connector_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"params": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsJSON,
+			},
+			"execute": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"space_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"message": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"service_message": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"data": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceActionsConnectorExecutionRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics // Warning or errors can be collected in a slice type
+	c := resourceClient(d, m)
+
+	var params map[string]interface{}
+	err := json.Unmarshal([]byte(d.Get("params").(string)), &params)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	connectorID := d.Get("connector_id").(string)
+	d.SetId(connectorID)
+	_ = d.Set("space_id", c.Space)
+
+	// every plan and refresh runs the connector, so it only happens on request
+	if !d.Get("execute").(bool) {
+		_ = d.Set("status", "")
+		_ = d.Set("message", "")
+		_ = d.Set("service_message", "")
+		_ = d.Set("data", "")
+		return diags
+	}
+
+	// a connector that fails to run is reported in the status, not as an error
+	execution, err := c.executeConnector(connectorID, params)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	data := ""
+	if execution.Data != nil {
+		dataValue, err := json.Marshal(execution.Data)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		data = string(dataValue)
+	}
+
+	_ = d.Set("status", execution.Status)
+	_ = d.Set("message", execution.Message)
+	_ = d.Set("service_message", execution.ServiceMessage)
+	_ = d.Set("data", data)
+
+	return diags
+}
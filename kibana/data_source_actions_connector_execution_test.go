@@ -0,0 +1,104 @@
+package kibana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccKibanaActionsConnectorExecution_basic(t *testing.T) {
+	resourceName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckKibanaActionsConnectorDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKibanaActionsConnectorExecution(resourceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.kibana_actions_connector_execution."+resourceName, "status", "ok"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceActionsConnectorExecutionRead(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/s/testSpace/api/actions/connector/c55b6eb0/_execute", r.URL.Path)
+
+		body, _ := ioutil.ReadAll(r.Body)
+		payload := map[string]interface{}{}
+		_ = json.Unmarshal(body, &payload)
+		assert.Equal(t, map[string]interface{}{"message": "test"}, payload["params"])
+
+		fmt.Fprint(w, `{
+			"connector_id": "c55b6eb0",
+			"status": "error",
+			"message": "error posting slack message",
+			"service_message": "invalid_token"
+		}`)
+	}))
+	defer ts.Close()
+
+	d := schema.TestResourceDataRaw(t, dataSourceActionsConnectorExecution().Schema, map[string]interface{}{
+		"connector_id": "c55b6eb0",
+		"params":       `{"message": "test"}`,
+		"execute":      true,
+	})
+
+	diags := dataSourceActionsConnectorExecutionRead(context.Background(), d, testClient(t, ts))
+	if assert.False(t, diags.HasError()) {
+		assert.Equal(t, "c55b6eb0", d.Id())
+		assert.Equal(t, "error", d.Get("status"))
+		assert.Equal(t, "error posting slack message", d.Get("message"))
+		assert.Equal(t, "invalid_token", d.Get("service_message"))
+		assert.Equal(t, "", d.Get("data"))
+	}
+}
+
+func TestDataSourceActionsConnectorExecutionRead_notExecuted(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+	defer ts.Close()
+
+	d := schema.TestResourceDataRaw(t, dataSourceActionsConnectorExecution().Schema, map[string]interface{}{
+		"connector_id": "c55b6eb0",
+		"params":       `{"message": "test"}`,
+	})
+
+	diags := dataSourceActionsConnectorExecutionRead(context.Background(), d, testClient(t, ts))
+	if assert.False(t, diags.HasError()) {
+		assert.Equal(t, "c55b6eb0", d.Id())
+		assert.Equal(t, "", d.Get("status"))
+	}
+}
+
+func testAccKibanaActionsConnectorExecution(resourceName string) string {
+	return fmt.Sprintf(`
+		resource "kibana_actions_connector" "%s" {
+		  name = "%s"
+
+		  index {
+		    index = "test-index"
+		  }
+		}
+
+		data "kibana_actions_connector_execution" "%s" {
+		  connector_id = kibana_actions_connector.%s.id
+		  params       = jsonencode({ "documents" : [{ "message" : "test" }] })
+		  execute      = true
+		}`, resourceName, resourceName, resourceName, resourceName)
+}
@@ -0,0 +1,96 @@
+package kibana
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccKibanaActionsConnectorDataSource_basic(t *testing.T) {
+	resourceName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckKibanaActionsConnectorDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKibanaActionsConnectorDataSource(resourceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.kibana_actions_connector."+resourceName, "id", "kibana_actions_connector."+resourceName, "id"),
+					resource.TestCheckResourceAttr("data.kibana_actions_connector."+resourceName, "connector_type_id", ".index"),
+					resource.TestCheckResourceAttr("data.kibana_actions_connector."+resourceName, "is_preconfigured", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceActionsConnectorRead(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/s/testSpace/api/actions/connectors", r.URL.Path)
+		fmt.Fprint(w, `[
+			{"id": "c55b6eb0", "name": "ops", "connector_type_id": ".slack", "is_preconfigured": false},
+			{"id": "my-slack", "name": "ops", "connector_type_id": ".slack", "is_preconfigured": true, "is_deprecated": true},
+			{"id": "a1b2c3d4", "name": "ops", "connector_type_id": ".index", "config": {"index": "alerts"}}
+		]`)
+	}))
+	defer ts.Close()
+
+	read := func(raw map[string]interface{}) (*schema.ResourceData, error) {
+		d := schema.TestResourceDataRaw(t, dataSourceActionsConnector().Schema, raw)
+		diags := dataSourceActionsConnectorRead(context.Background(), d, testClient(t, ts))
+		if diags.HasError() {
+			return d, fmt.Errorf("%s", diags[0].Summary)
+		}
+		return d, nil
+	}
+
+	d, err := read(map[string]interface{}{"name": "ops", "connector_type_id": ".index"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "a1b2c3d4", d.Id())
+		assert.Equal(t, "testSpace", d.Get("space_id"))
+		assert.Equal(t, `{"index":"alerts"}`, d.Get("config"))
+	}
+
+	d, err = read(map[string]interface{}{"id": "my-slack"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "ops", d.Get("name"))
+		assert.Equal(t, true, d.Get("is_preconfigured"))
+		assert.Equal(t, true, d.Get("is_deprecated"))
+		assert.Equal(t, "", d.Get("config"))
+	}
+
+	_, err = read(map[string]interface{}{"name": "ops", "connector_type_id": ".slack"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `found 2 connectors with name "ops" and type ".slack" (c55b6eb0, my-slack)`)
+	}
+
+	_, err = read(map[string]interface{}{"name": "dev"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `no connector found with name "dev"`)
+	}
+}
+
+func testAccKibanaActionsConnectorDataSource(resourceName string) string {
+	return fmt.Sprintf(`
+		resource "kibana_actions_connector" "%s" {
+		  name = "%s"
+
+		  index {
+		    index = "test-index"
+		  }
+		}
+
+		data "kibana_actions_connector" "%s" {
+		  name              = kibana_actions_connector.%s.name
+		  connector_type_id = ".index"
+		}`, resourceName, resourceName, resourceName, resourceName)
+}
@@ -0,0 +1,96 @@
+package kibana
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceConnectorTypes() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceConnectorTypesRead,
+		Schema: map[string]*schema.Schema{
+			"space_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"connector_types": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"minimum_license_required": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"enabled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"enabled_in_config": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"enabled_in_license": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"supported_feature_ids": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceConnectorTypesRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics // Warning or errors can be collected in a slice type
+	c := resourceClient(d, m)
+
+	types, err := c.getConnectorTypes()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = d.Set("connector_types", flattenConnectorTypes(types))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	_ = d.Set("space_id", c.Space)
+
+	d.SetId(c.Space)
+
+	return diags
+}
+
+// flattenConnectorTypes - maps the connector types offered by the server to
+// the data source attributes
+func flattenConnectorTypes(types []connectorTypeInfo) []interface{} {
+	result := make([]interface{}, 0, len(types))
+	for _, t := range types {
+		result = append(result, map[string]interface{}{
+			"id":                       t.ID,
+			"name":                     t.Name,
+			"minimum_license_required": t.MinimumLicenseRequired,
+			"enabled":                  t.Enabled,
+			"enabled_in_config":        t.EnabledInConfig,
+			"enabled_in_license":       t.EnabledInLicense,
+			"supported_feature_ids":    t.SupportedFeatureIDs,
+		})
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+package kibana
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccKibanaConnectorTypes_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "kibana_connector_types" "all" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.kibana_connector_types.all", "space_id", os.Getenv("KIBANA_SPACE")),
+					resource.TestCheckTypeSetElemNestedAttrs("data.kibana_connector_types.all", "connector_types.*", map[string]string{
+						"id":   ".index",
+						"name": "Index",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceConnectorTypesRead(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/s/otherSpace/api/actions/connector_types", r.URL.Path)
+		fmt.Fprint(w, `[{
+			"id": ".jira",
+			"name": "Jira",
+			"enabled": false,
+			"enabled_in_config": true,
+			"enabled_in_license": false,
+			"minimum_license_required": "gold",
+			"supported_feature_ids": ["alerting", "cases"]
+		}]`)
+	}))
+	defer ts.Close()
+
+	d := schema.TestResourceDataRaw(t, dataSourceConnectorTypes().Schema, map[string]interface{}{
+		"space_id": "otherSpace",
+	})
+
+	diags := dataSourceConnectorTypesRead(context.Background(), d, testClient(t, ts))
+	if !assert.False(t, diags.HasError()) {
+		return
+	}
+	assert.Equal(t, "otherSpace", d.Id())
+	assert.Equal(t, 1, d.Get("connector_types.#"))
+	assert.Equal(t, ".jira", d.Get("connector_types.0.id"))
+	assert.Equal(t, "gold", d.Get("connector_types.0.minimum_license_required"))
+	assert.Equal(t, false, d.Get("connector_types.0.enabled"))
+	assert.Equal(t, true, d.Get("connector_types.0.enabled_in_config"))
+	assert.Equal(t, false, d.Get("connector_types.0.enabled_in_license"))
+	assert.Equal(t, []interface{}{"alerting", "cases"}, d.Get("connector_types.0.supported_feature_ids"))
+}
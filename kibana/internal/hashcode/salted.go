@@ -0,0 +1,37 @@
+package hashcode
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+const saltSize = 16
+
+// NewSalt generates a random salt for Salted, hex encoded.
+func NewSalt() (string, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(salt), nil
+}
+
+// Salted hashes a secret with SHA-256 and a salt.
+//
+// The result is hex encoded and can be checked against a secret with Match and
+// the same salt. The secret cannot be recovered from it, which makes it safe
+// to keep in the Terraform state, and a different salt for each resource
+// keeps the same secret from having the same hash.
+func Salted(salt, s string) string {
+	h := sha256.New()
+	h.Write([]byte(salt))
+	h.Write([]byte(s))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Match checks if a Salted hash was computed from a secret and a salt.
+func Match(hashed, salt, s string) bool {
+	return subtle.ConstantTimeCompare([]byte(Salted(salt, s)), []byte(hashed)) == 1
+}
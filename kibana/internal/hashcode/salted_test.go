@@ -0,0 +1,48 @@
+package hashcode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSalted(t *testing.T) {
+	secret := `{"webhookUrl":"https://hooks.slack.com/services/T000/B000/XXXX"}`
+
+	saltA, err := NewSalt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saltB, err := NewSalt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saltA == saltB {
+		t.Fatalf("expected different salts, got %s twice", saltA)
+	}
+
+	a := Salted(saltA, secret)
+	if a != Salted(saltA, secret) {
+		t.Fatalf("expected the same hash for the same salt")
+	}
+	if a == Salted(saltB, secret) {
+		t.Fatalf("expected different hashes for different salts, got %s twice", a)
+	}
+	if strings.Contains(a, "hooks.slack.com") {
+		t.Fatalf("the secret leaked into the hash: %s", a)
+	}
+
+	if !Match(a, saltA, secret) {
+		t.Fatalf("expected %s to match the secret", a)
+	}
+	if Match(a, saltA, secret+" ") || Match(a, saltB, secret) {
+		t.Fatalf("expected %s not to match a different secret or salt", a)
+	}
+}
+
+func TestMatch_invalid(t *testing.T) {
+	for _, hashed := range []string{"", "secret", `{"a":"b"}`} {
+		if Match(hashed, "", hashed) {
+			t.Fatalf("expected %q not to match", hashed)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// NormalizeJSON returns the canonical form of a JSON document.
+//
+// Object keys are sorted, insignificant whitespace is removed and object
+// members with a null value are dropped. An empty document or a null
+// document is equivalent to an empty object.
+func NormalizeJSON(s string) (string, error) {
+	if strings.TrimSpace(s) == "" {
+		return "{}", nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return "", err
+	}
+	if v == nil {
+		return "{}", nil
+	}
+
+	b, err := json.Marshal(removeNulls(v))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// JSONEqual checks if two JSON documents are semantically equal.
+//
+// Documents that are not valid JSON are only equal if they are the same.
+func JSONEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	na, err := NormalizeJSON(a)
+	if err != nil {
+		return false
+	}
+	nb, err := NormalizeJSON(b)
+	if err != nil {
+		return false
+	}
+	return na == nb
+}
+
+// SuppressEquivalentJSONDiffs is a schema.SchemaDiffSuppressFunc that ignores
+// differences between semantically equal JSON documents.
+func SuppressEquivalentJSONDiffs(_, old, new string, _ *schema.ResourceData) bool {
+	return JSONEqual(old, new)
+}
+
+func removeNulls(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if value == nil {
+				delete(v, key)
+				continue
+			}
+			v[key] = removeNulls(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = removeNulls(value)
+		}
+	}
+	return v
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestNormalizeJSON(t *testing.T) {
+	cases := map[string]string{
+		`{"b": 1, "a": {"d": [1, 2], "c": "x"}}`: `{"a":{"c":"x","d":[1,2]},"b":1}`,
+		`{"index": "test", "refresh": null}`:     `{"index":"test"}`,
+		`[{"a": null, "b": 1.0}]`:                `[{"b":1}]`,
+		``:                                       `{}`,
+		`null`:                                   `{}`,
+		"{\n  \"webhookUrl\": \"https://x\"\n}\n": `{"webhookUrl":"https://x"}`,
+	}
+	for input, expected := range cases {
+		actual, err := NormalizeJSON(input)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", input, err)
+		}
+		if actual != expected {
+			t.Fatalf("bad: %q\n\texpected: %s\n\tactual:   %s", input, expected, actual)
+		}
+	}
+
+	if _, err := NormalizeJSON(`{"a":`); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+}
+
+func TestJSONEqual(t *testing.T) {
+	equal := [][2]string{
+		{`{"a": 1, "b": 2}`, `{"b":2,"a":1}`},
+		{`{"a": 1, "b": null}`, `{"a": 1}`},
+		{``, `{}`},
+		{`not json`, `not json`},
+	}
+	for _, c := range equal {
+		if !JSONEqual(c[0], c[1]) {
+			t.Fatalf("expected %q and %q to be equal", c[0], c[1])
+		}
+	}
+
+	different := [][2]string{
+		{`{"a": 1}`, `{"a": 2}`},
+		{`{"a": [1, 2]}`, `{"a": [2, 1]}`},
+		{`{"a": 1}`, `not json`},
+	}
+	for _, c := range different {
+		if JSONEqual(c[0], c[1]) {
+			t.Fatalf("expected %q and %q to be different", c[0], c[1])
+		}
+	}
+}
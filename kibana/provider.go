@@ -2,6 +2,8 @@ package kibana
 
 import (
 	"context"
+	"log"
+	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -13,20 +15,59 @@ func Provider() *schema.Provider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"host": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("KIBANA_URL", nil),
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("KIBANA_URL", nil),
+				ConflictsWith: []string{"cloud_id"},
+			},
+			"cloud_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("KIBANA_CLOUD_ID", nil),
+				ConflictsWith: []string{"host"},
 			},
 			"username": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("KIBANA_USERNAME", nil),
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("KIBANA_USERNAME", nil),
+				ConflictsWith: []string{"api_key"},
 			},
 			"password": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("KIBANA_PASSWORD", nil),
+				ConflictsWith: []string{"api_key"},
+			},
+			"api_key": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("KIBANA_API_KEY", nil),
+				ConflictsWith: []string{"username", "password"},
+			},
+			"ca_certs": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Sensitive:   true,
-				DefaultFunc: schema.EnvDefaultFunc("KIBANA_PASSWORD", nil),
+				DefaultFunc: schema.EnvDefaultFunc("KIBANA_CA_CERTS", nil),
+			},
+			"client_cert": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("KIBANA_CLIENT_CERT", nil),
+				RequiredWith: []string{"client_key"},
+			},
+			"client_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				DefaultFunc:  schema.EnvDefaultFunc("KIBANA_CLIENT_KEY", nil),
+				RequiredWith: []string{"client_cert"},
+			},
+			"insecure": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("KIBANA_INSECURE", false),
 			},
 			"space": {
 				Type:        schema.TypeString,
@@ -36,14 +77,17 @@ func Provider() *schema.Provider {
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"kibana_actions_connector": resourceActionsConnector(),
-			"kibana_alerting_rule":     resourceAlertingRule(),
+			"kibana_actions_connector":  resourceActionsConnector(),
+			"kibana_alerting_rule":      resourceAlertingRule(),
+			"kibana_maintenance_window": resourceMaintenanceWindow(),
+			"kibana_role":               resourceRole(),
+			"kibana_space":              resourceSpace(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"kibana_actions_connector":           dataSourceActionsConnector(),
+			"kibana_actions_connector_execution": dataSourceActionsConnectorExecution(),
+			"kibana_connector_types":             dataSourceConnectorTypes(),
 		},
-		// DataSourcesMap: map[string]*schema.Resource{
-		// 	"hashicups_coffees":     dataSourceCoffees(),
-		// 	"hashicups_ingredients": dataSourceIngredients(),
-		// 	"hashicups_order":       dataSourceOrder(),
-		// },
 		ConfigureContextFunc: providerConfigure,
 	}
 }
@@ -51,19 +95,80 @@ func Provider() *schema.Provider {
 func providerConfigure(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	username := d.Get("username").(string)
 	password := d.Get("password").(string)
+	apiKey := d.Get("api_key").(string)
 	space := d.Get("space").(string)
 	host := d.Get("host").(string)
 
 	var diags diag.Diagnostics // Warning or errors can be collected in a slice type
+
+	if cloudID := d.Get("cloud_id").(string); cloudID != "" {
+		kibanaURL, err := cloudIDKibanaURL(cloudID)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Invalid Elastic Cloud ID",
+				Detail:   "Unable to decode the Kibana endpoint from cloud_id: " + err.Error(),
+			})
+			return nil, diags
+		}
+		log.Printf("[INFO] Using Kibana endpoint %s from the Elastic Cloud ID", kibanaURL)
+		host = kibanaURL
+	}
+
+	authMode := "basic authentication"
+	if apiKey != "" {
+		authMode = "API key authentication"
+		if username != "" || password != "" {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Using API key authentication",
+				Detail:   "Both an API key and a username or password are set; the username and password are ignored",
+			})
+		}
+	}
+	log.Printf("[INFO] Configuring Kibana client with %s", authMode)
+
 	c, err := gk.NewClient(&host, &username, &password, &space)
 	if err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Unable to create Kibana client",
-			Detail:   "Unable to create Kibana client with basic authentication",
+			Detail:   "Unable to create Kibana client with " + authMode,
 		})
 		return nil, diags
 	}
 
-	return c, diags
+	tlsOpts := tlsOptions{
+		caCerts:    d.Get("ca_certs").(string),
+		clientCert: d.Get("client_cert").(string),
+		clientKey:  d.Get("client_key").(string),
+		insecure:   d.Get("insecure").(bool),
+	}
+	if tlsOpts.isSet() {
+		tlsConfig, err := newTLSConfig(tlsOpts)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to configure TLS for the Kibana client",
+				Detail:   err.Error(),
+			})
+			return nil, diags
+		}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		c.HTTPClient.Transport = transport
+	}
+
+	if apiKey != "" {
+		c.HTTPClient.Transport = &apiKeyTransport{apiKey: apiKey, next: c.HTTPClient.Transport}
+	}
+
+	client := &apiClient{Client: c}
+	diags = append(diags, client.checkConnection()...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	return client, diags
 }
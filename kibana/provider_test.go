@@ -1,7 +1,10 @@
 package kibana
 
 import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"testing"
 	"os"
 )
@@ -19,9 +22,20 @@ func init() {
 }
 
 
+func TestProvider(t *testing.T) {
+	if err := Provider().InternalValidate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
-	if v := os.Getenv("KIBANA_PASSWORD"); v == "" {
-		t.Fatal("KIBANA_PASSWORD must be set for acceptance tests")
+	if os.Getenv("KIBANA_API_KEY") == "" {
+		if v := os.Getenv("KIBANA_PASSWORD"); v == "" {
+			t.Fatal("KIBANA_PASSWORD or KIBANA_API_KEY must be set for acceptance tests")
+		}
+		if v := os.Getenv("KIBANA_USERNAME"); v == "" {
+			t.Fatal("KIBANA_USERNAME or KIBANA_API_KEY must be set for acceptance tests")
+		}
 	}
 	if v := os.Getenv("KIBANA_SPACE"); v == "" {
 		t.Fatal("KIBANA_SPACE must be set for acceptance tests")
@@ -29,7 +43,16 @@ func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("KIBANA_URL"); v == "" {
 		t.Fatal("KIBANA_URL must be set for acceptance tests")
 	}
-	if v := os.Getenv("KIBANA_USERNAME"); v == "" {
-		t.Fatal("KIBANA_USERNAME must be set for acceptance tests")
+}
+
+// testAccSpaceObjectImportID - builds the <space_id>/<object_id> import ID of a resource
+func testAccSpaceObjectImportID(resourceName string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("not found: %s", resourceName)
+		}
+
+		return fmt.Sprintf("%s/%s", rs.Primary.Attributes["space_id"], rs.Primary.ID), nil
 	}
 }
@@ -3,90 +3,164 @@ package kibana
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	gk "github.com/renato0307/go-kibana/kibana"
+	"github.com/renato0307/terraform-provider-kibana/kibana/internal/hashcode"
+	"github.com/renato0307/terraform-provider-kibana/kibana/internal/utils"
 )
 
 func resourceActionsConnector() *schema.Resource {
+	resourceSchema := map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+			Computed: false,
+		},
+		"connector_type_id": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			Computed:      true,
+			ForceNew:      true,
+			ConflictsWith: connectorTypeBlockNames(),
+		},
+		"is_preconfigured": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: false,
+		},
+		"is_missing_secrets": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: false,
+		},
+		"config": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         false,
+			ExactlyOneOf:     append(connectorTypeBlockNames(), "config"),
+			ValidateFunc:     validation.StringIsJSON,
+			DiffSuppressFunc: utils.SuppressEquivalentJSONDiffs,
+		},
+		"secrets": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         false,
+			Sensitive:        true,
+			ConflictsWith:    connectorTypeBlockNames(),
+			ValidateFunc:     validation.StringIsJSON,
+			DiffSuppressFunc: suppressUnchangedConnectorSecrets,
+		},
+		"secrets_salt": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"space_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			ForceNew: true,
+		},
+	}
+
+	// adds the typed connector blocks
+	for name, blockSchema := range connectorTypeSchemas() {
+		resourceSchema[name] = blockSchema
+	}
+
 	return &schema.Resource{
 		CreateContext: resourceActionsConnectorCreate,
 		ReadContext:   resourceActionsConnectorRead,
 		UpdateContext: resourceActionsConnectorUpdate,
 		DeleteContext: resourceActionsConnectorDelete,
-		Schema: map[string]*schema.Schema{
-			"id": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-			},
-			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				Computed: false,
-			},
-			"connector_type_id": {
-				Type:     schema.TypeString,
-				Required: true,
-				Computed: false,
-			},
-			"is_preconfigured": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Computed: false,
-			},
-			"is_missing_secrets": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Computed: false,
-			},
-			"config": {
-				Type:     schema.TypeString,
-				Required: true,
-				Computed: false,
-			},
-			"secrets": {
-				Type:      schema.TypeString,
-				Optional:  true,
-				Computed:  false,
-				Sensitive: true,
-			},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceImportSpaceObject,
 		},
+		CustomizeDiff: resourceActionsConnectorCustomizeDiff,
+		Schema:        resourceSchema,
+	}
+}
+
+// resourceActionsConnectorCustomizeDiff - sets the connector type of typed
+// connector blocks, requires it for the generic config and checks that the
+// server offers it
+func resourceActionsConnectorCustomizeDiff(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if _, t, ok := connectorTypeBlock(d); ok {
+		if d.Get("connector_type_id").(string) != t.typeID {
+			err := d.SetNew("connector_type_id", t.typeID)
+			if err != nil {
+				return err
+			}
+		}
+	} else if d.Get("connector_type_id").(string) == "" && d.NewValueKnown("connector_type_id") {
+		return fmt.Errorf("connector_type_id is required when using config")
+	}
+
+	if m == nil || !d.HasChange("connector_type_id") || !d.NewValueKnown("connector_type_id") {
+		return nil
 	}
+	return validateConnectorTypeID(resourceClient(d, m), d.Get("connector_type_id").(string))
+}
+
+// validateConnectorTypeID - checks that the server offers a connector type and
+// that it is enabled
+func validateConnectorTypeID(c *apiClient, connectorTypeID string) error {
+	types, err := c.getConnectorTypes()
+	if err != nil {
+		return fmt.Errorf("unable to retrieve the connector types to validate %q: %w", connectorTypeID, err)
+	}
+
+	var ids []string
+	for _, t := range types {
+		if t.ID != connectorTypeID {
+			ids = append(ids, t.ID)
+			continue
+		}
+		if !t.EnabledInLicense {
+			return fmt.Errorf("connector type %q is not enabled by the license of the server, it requires a %s license",
+				connectorTypeID, t.MinimumLicenseRequired)
+		}
+		if !t.Enabled {
+			return fmt.Errorf("connector type %q is not enabled by the server", connectorTypeID)
+		}
+		return nil
+	}
+	sort.Strings(ids)
+
+	return fmt.Errorf("connector type %q is not offered by the server, the available types are: %s",
+		connectorTypeID, strings.Join(ids, ", "))
 }
 
 func resourceActionsConnectorCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics // Warning or errors can be collected in a slice type
-	c := m.(*gk.Client)
+	c := resourceClient(d, m)
 
-	connector := gk.CreateConnector{
-		Name:            d.Get("name").(string),
-		ConnectorTypeId: d.Get("connector_type_id").(string),
+	connectorTypeID, config, secrets, err := expandActionsConnector(d)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	log.Printf("Unmarshalling config: %s", d.Get("config").(string))
-	var config map[string]interface{}
-	configValue, ok := d.GetOk("config")
-	if ok {
-		err := json.Unmarshal([]byte(configValue.(string)), &config)
-		connector.Config = config
-		if err != nil {
-			return diag.FromErr(err)
-		}
+	err = setConnectorSecretsSalt(d)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	log.Printf("Unmarshalling secrets: %s", d.Get("secrets").(string))
-	var secrets map[string]interface{}
-	secretsValue, ok := d.GetOk("secrets")
-	if ok {
-		err := json.Unmarshal([]byte(secretsValue.(string)), &secrets)
-		connector.Secrets = secrets
-		if err != nil {
-			return diag.FromErr(err)
-		}
+	connector := gk.CreateConnector{
+		Name:            d.Get("name").(string),
+		ConnectorTypeId: connectorTypeID,
+		Config:          config,
+		Secrets:         secrets,
 	}
 
 	newConnector, err := c.CreateConnector(connector)
@@ -95,6 +169,11 @@ func resourceActionsConnectorCreate(ctx context.Context, d *schema.ResourceData,
 	}
 	d.SetId(newConnector.ID)
 
+	err = hashConnectorSecrets(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	resourceActionsConnectorRead(ctx, d, m)
 
 	return diags
@@ -102,11 +181,14 @@ func resourceActionsConnectorCreate(ctx context.Context, d *schema.ResourceData,
 
 func resourceActionsConnectorRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics // Warning or errors can be collected in a slice type
-	c := m.(*gk.Client)
+	c := resourceClient(d, m)
 
 	connectorId := d.Id()
 
 	connector, err := c.GetConnector(connectorId)
+	if isNotFound(err) {
+		return resourceNotFound(d, "connector")
+	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -115,57 +197,62 @@ func resourceActionsConnectorRead(_ context.Context, d *schema.ResourceData, m i
 	_ = d.Set("connector_type_id", connector.ConnectorTypeId)
 	_ = d.Set("is_preconfigured", connector.IsPreconfigured)
 	_ = d.Set("is_missing_secrets", connector.IsMissingSecrets)
+	_ = d.Set("space_id", c.Space)
 
-	if connector.Config != nil {
-		configValue, err := json.Marshal(connector.Config)
-		if err != nil {
-			return diag.FromErr(err)
+	// uses the typed connector block, unless the connector is managed with
+	// the generic config or its type has no typed block
+	if _, ok := d.GetOk("config"); !ok {
+		for name, t := range connectorTypes {
+			if t.typeID == connector.ConnectorTypeId {
+				err = flattenConnectorTypeBlock(d, name, connector.Config)
+				if err != nil {
+					return diag.FromErr(err)
+				}
+				return diags
+			}
 		}
-		_ = d.Set("config", string(configValue))
 	}
 
-	if connector.Secrets != nil {
-		secretsValue, err := json.Marshal(connector.Secrets)
+	if connector.Config != nil {
+		configValue, err := json.Marshal(connector.Config)
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		_ = d.Set("secrets", string(secretsValue))
+		_ = d.Set("config", string(configValue))
 	}
 
 	return diags
 }
 
 func resourceActionsConnectorUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*gk.Client)
+	c := resourceClient(d, m)
 
-	connector := gk.UpdateConnector{
-		Name: d.Get("name").(string),
+	_, config, secrets, err := expandActionsConnector(d)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	log.Printf("Unmarshalling config: %s", d.Get("config").(string))
-	var config map[string]interface{}
-	configValue, ok := d.GetOk("config")
-	if ok {
-		err := json.Unmarshal([]byte(configValue.(string)), &config)
-		connector.Config = config
-		if err != nil {
-			return diag.FromErr(err)
-		}
+	// imported connectors have no salt yet
+	err = setConnectorSecretsSalt(d)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	log.Printf("Unmarshalling secrets: %s", d.Get("secrets").(string))
-	var secrets map[string]interface{}
-	secretsValue, ok := d.GetOk("secrets")
-	if ok {
-		err := json.Unmarshal([]byte(secretsValue.(string)), &secrets)
-		connector.Secrets = secrets
-		if err != nil {
-			return diag.FromErr(err)
-		}
+	connector := gk.UpdateConnector{
+		Name:    d.Get("name").(string),
+		Config:  config,
+		Secrets: secrets,
 	}
 
 	connectorID := d.Id()
-	_, err := c.UpdateConnector(connectorID, connector)
+	_, err = c.UpdateConnector(connectorID, connector)
+	if err != nil {
+		// keeps the previous state, without the cleartext secrets
+		d.Partial(true)
+		return diag.FromErr(err)
+	}
+
+	err = hashConnectorSecrets(d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -177,12 +264,12 @@ func resourceActionsConnectorUpdate(ctx context.Context, d *schema.ResourceData,
 
 func resourceActionsConnectorDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics // Warning or errors can be collected in a slice type
-	c := m.(*gk.Client)
+	c := resourceClient(d, m)
 
 	connectorID := d.Id()
 
 	err := c.DeleteConnector(connectorID)
-	if err != nil {
+	if err != nil && !isNotFound(err) {
 		return diag.FromErr(err)
 	}
 
@@ -192,3 +279,154 @@ func resourceActionsConnectorDelete(_ context.Context, d *schema.ResourceData, m
 
 	return diags
 }
+
+// expandActionsConnector - maps the resource data to the connector type,
+// config and secrets, either from a typed connector block or the generic
+// config and secrets
+func expandActionsConnector(d *schema.ResourceData) (string, map[string]interface{}, map[string]interface{}, error) {
+	if name, t, ok := connectorTypeBlock(d); ok {
+		config, secrets := expandConnectorTypeBlock(d, name)
+		return t.typeID, config, secrets, nil
+	}
+
+	var config map[string]interface{}
+	configValue, ok := d.GetOk("config")
+	if ok {
+		err := json.Unmarshal([]byte(configValue.(string)), &config)
+		if err != nil {
+			return "", nil, nil, err
+		}
+	}
+
+	var secrets map[string]interface{}
+	secretsValue, ok := d.GetOk("secrets")
+	if ok {
+		err := json.Unmarshal([]byte(secretsValue.(string)), &secrets)
+		if err != nil {
+			return "", nil, nil, err
+		}
+	}
+
+	return d.Get("connector_type_id").(string), config, secrets, nil
+}
+
+// setConnectorSecretsSalt - generates the random salt of the hashes of the
+// connector secrets, unless the connector already has one
+func setConnectorSecretsSalt(d *schema.ResourceData) error {
+	if d.Get("secrets_salt").(string) != "" {
+		return nil
+	}
+
+	salt, err := hashcode.NewSalt()
+	if err != nil {
+		return fmt.Errorf("unable to generate the salt of the connector secrets: %w", err)
+	}
+	return d.Set("secrets_salt", salt)
+}
+
+// hashConnectorSecrets - replaces the secrets sent to Kibana with their
+// salted hash, which is all the state keeps of them
+func hashConnectorSecrets(d *schema.ResourceData) error {
+	salt := d.Get("secrets_salt").(string)
+
+	if name, t, ok := connectorTypeBlock(d); ok {
+		block, _ := d.Get(name).([]interface{})[0].(map[string]interface{})
+		for fieldName, field := range t.fields {
+			key := fmt.Sprintf("%s.0.%s", name, fieldName)
+			if secret, _ := block[fieldName].(string); field.secret && secret != "" && d.HasChange(key) {
+				block[fieldName] = hashcode.Salted(salt, secret)
+			}
+		}
+		return d.Set(name, []interface{}{block})
+	}
+
+	if secrets := d.Get("secrets").(string); secrets != "" && d.HasChange("secrets") {
+		return d.Set("secrets", hashcode.Salted(salt, connectorSecretHashInput("secrets", secrets)))
+	}
+	return nil
+}
+
+// connectorSecretHashInput - returns the value of a secret attribute that is
+// hashed, as JSON with the same content always gets the same hash input
+func connectorSecretHashInput(k, secret string) string {
+	if k == "secrets" {
+		if normalized, err := utils.NormalizeJSON(secret); err == nil {
+			return normalized
+		}
+	}
+	return secret
+}
+
+// connectorSecretKeys - returns the attributes with the secrets of the
+// connector, from its typed connector block or the generic secrets
+func connectorSecretKeys(d *schema.ResourceData) []string {
+	name, t, ok := connectorTypeBlock(d)
+	if !ok {
+		return []string{"secrets"}
+	}
+
+	var keys []string
+	for fieldName, field := range t.fields {
+		if field.secret {
+			keys = append(keys, fmt.Sprintf("%s.0.%s", name, fieldName))
+		}
+	}
+	return keys
+}
+
+// suppressUnchangedConnectorSecrets - suppresses the diff of secrets whose
+// hash in the state matches the configuration
+//
+// Kibana requires all the secrets on every update, so the diff is kept when
+// any of them or any other attribute sent to Kibana changes, which makes the
+// cleartext secrets available to the update.
+func suppressUnchangedConnectorSecrets(_, old, _ string, d *schema.ResourceData) bool {
+	return old != "" && !connectorPayloadChanged(d) && !connectorSecretsChanged(d)
+}
+
+// connectorSecretsChanged - checks if any configured secret does not match
+// its hash in the state
+func connectorSecretsChanged(d *schema.ResourceData) bool {
+	salt := d.Get("secrets_salt").(string)
+
+	// during the plan, the resource data holds the configured values
+	for _, key := range connectorSecretKeys(d) {
+		o, n := d.GetChange(key)
+		hashed, secret := o.(string), n.(string)
+		if hashed == "" && secret == "" {
+			continue
+		}
+		if !hashcode.Match(hashed, salt, connectorSecretHashInput(key, secret)) {
+			return true
+		}
+	}
+	return false
+}
+
+// connectorPayloadChanged - checks if any attribute sent to Kibana with the
+// secrets changed
+func connectorPayloadChanged(d *schema.ResourceData) bool {
+	if d.HasChanges("name", "is_preconfigured", "is_missing_secrets") {
+		return true
+	}
+
+	oldConfig, newConfig := d.GetChange("config")
+	if !utils.JSONEqual(oldConfig.(string), newConfig.(string)) {
+		return true
+	}
+
+	for _, name := range connectorTypeBlockNames() {
+		if d.HasChange(name + ".#") {
+			return true
+		}
+	}
+
+	if name, t, ok := connectorTypeBlock(d); ok {
+		for fieldName, field := range t.fields {
+			if !field.secret && d.HasChange(fmt.Sprintf("%s.0.%s", name, fieldName)) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -1,11 +1,18 @@
 package kibana
 
 import (
+	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/renato0307/terraform-provider-kibana/kibana/internal/hashcode"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
-	gk "github.com/renato0307/go-kibana/kibana"
 	"strings"
 	"testing"
 )
@@ -22,10 +29,319 @@ func TestAccKibanaActionsConnector_basic(t *testing.T) {
 				Config: testAccKibanaActionsConnector(resourceName),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckKibanaActionsConnectorExists(resourceName),
+					resource.TestCheckResourceAttr("kibana_actions_connector."+resourceName, "space_id", os.Getenv("KIBANA_SPACE")),
 				),
 			},
+			{
+				ResourceName:      "kibana_actions_connector." + resourceName,
+				ImportState:       true,
+				ImportStateIdFunc: testAccSpaceObjectImportID("kibana_actions_connector." + resourceName),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccKibanaActionsConnector_typed(t *testing.T) {
+	resourceName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckKibanaActionsConnectorDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKibanaActionsConnectorTyped(resourceName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKibanaActionsConnectorExists(resourceName),
+					resource.TestCheckResourceAttr("kibana_actions_connector."+resourceName, "connector_type_id", ".index"),
+					resource.TestCheckResourceAttr("kibana_actions_connector."+resourceName, "index.0.index", "test-index"),
+					resource.TestCheckResourceAttr("kibana_actions_connector."+resourceName, "index.0.refresh", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestExpandFlattenConnectorTypeBlock(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceActionsConnector().Schema, map[string]interface{}{
+		"name": "my-connector",
+		"webhook": []interface{}{
+			map[string]interface{}{
+				"url":      "https://example.com/hook",
+				"headers":  map[string]interface{}{"x-team": "sre"},
+				"user":     "bot",
+				"password": "secret",
+			},
+		},
+	})
+
+	connectorTypeID, config, secrets, err := expandActionsConnector(d)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, ".webhook", connectorTypeID)
+	assert.Equal(t, map[string]interface{}{
+		"url":     "https://example.com/hook",
+		"headers": map[string]interface{}{"x-team": "sre"},
+		"method":  "post",
+		"hasAuth": true,
+	}, config)
+	assert.Equal(t, map[string]interface{}{"user": "bot", "password": "secret"}, secrets)
+
+	// only the salted hash of the secrets is kept
+	if !assert.NoError(t, setConnectorSecretsSalt(d)) || !assert.NoError(t, hashConnectorSecrets(d)) {
+		return
+	}
+	salt := d.Get("secrets_salt").(string)
+	assert.NotEmpty(t, salt)
+
+	err = flattenConnectorTypeBlock(d, "webhook", map[string]interface{}{
+		"url":     "https://example.com/other",
+		"method":  "put",
+		"hasAuth": false,
+		"headers": nil,
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "https://example.com/other", d.Get("webhook.0.url"))
+		assert.Equal(t, "put", d.Get("webhook.0.method"))
+		assert.Equal(t, false, d.Get("webhook.0.has_auth"))
+		assert.Equal(t, hashcode.Salted(salt, "secret"), d.Get("webhook.0.password"))
+		assert.Equal(t, hashcode.Salted(salt, "bot"), d.Get("webhook.0.user"))
+	}
+}
+
+func TestResourceActionsConnectorSecretsDiff(t *testing.T) {
+	secrets := `{"webhookUrl": "https://hooks.slack.com/services/T000/B000/XXXX"}`
+	state := &terraform.InstanceState{
+		ID: "c55b6eb0",
+		Attributes: map[string]string{
+			"id":                "c55b6eb0",
+			"name":              "my-connector",
+			"connector_type_id": ".slack",
+			"config":            "{}",
+			"secrets":           hashcode.Salted("5a17", `{"webhookUrl":"https://hooks.slack.com/services/T000/B000/XXXX"}`),
+			"secrets_salt":      "5a17",
+			"space_id":          "default",
+		},
+	}
+
+	diff := func(config map[string]interface{}) *terraform.InstanceDiff {
+		d, err := resourceActionsConnector().Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	// same secrets, formatted differently
+	d := diff(map[string]interface{}{
+		"name":              "my-connector",
+		"connector_type_id": ".slack",
+		"config":            "{}",
+		"secrets":           secrets,
+	})
+	assert.Nil(t, d)
+
+	// changed secrets
+	d = diff(map[string]interface{}{
+		"name":              "my-connector",
+		"connector_type_id": ".slack",
+		"config":            "{}",
+		"secrets":           `{"webhookUrl": "https://hooks.slack.com/services/T000/B000/YYYY"}`,
+	})
+	if assert.NotNil(t, d) && assert.Contains(t, d.Attributes, "secrets") {
+		assert.False(t, d.RequiresNew())
+	}
+
+	// unchanged secrets are sent again with a changed name
+	d = diff(map[string]interface{}{
+		"name":              "my-renamed-connector",
+		"connector_type_id": ".slack",
+		"config":            "{}",
+		"secrets":           secrets,
+	})
+	if assert.NotNil(t, d) && assert.Contains(t, d.Attributes, "secrets") {
+		assert.False(t, d.RequiresNew())
+		assert.Equal(t, secrets, d.Attributes["secrets"].New)
+	}
+}
+
+func TestResourceActionsConnectorSecretsDiff_typed(t *testing.T) {
+	state := schema.TestResourceDataRaw(t, resourceActionsConnector().Schema, map[string]interface{}{
+		"name": "my-connector",
+		"webhook": []interface{}{
+			map[string]interface{}{
+				"url":      "https://example.com/hook",
+				"user":     hashcode.Salted("5a17", "bot"),
+				"password": hashcode.Salted("5a17", "secret"),
+			},
 		},
 	})
+	state.SetId("c55b6eb0")
+	_ = state.Set("connector_type_id", ".webhook")
+	_ = state.Set("secrets_salt", "5a17")
+	_ = state.Set("space_id", "default")
+
+	diff := func(url, password string) *terraform.InstanceDiff {
+		d, err := resourceActionsConnector().Diff(context.Background(), state.State(), terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name": "my-connector",
+			"webhook": []interface{}{
+				map[string]interface{}{
+					"url":      url,
+					"user":     "bot",
+					"password": password,
+				},
+			},
+		}), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	assert.Nil(t, diff("https://example.com/hook", "secret"))
+
+	// the unchanged user is sent again with the changed password
+	d := diff("https://example.com/hook", "other-secret")
+	if assert.NotNil(t, d) {
+		assert.False(t, d.RequiresNew())
+		assert.Equal(t, "other-secret", d.Attributes["webhook.0.password"].New)
+		assert.Equal(t, "bot", d.Attributes["webhook.0.user"].New)
+	}
+
+	// and with a changed url
+	d = diff("https://example.com/other", "secret")
+	if assert.NotNil(t, d) {
+		assert.False(t, d.RequiresNew())
+		assert.Equal(t, "secret", d.Attributes["webhook.0.password"].New)
+		assert.Equal(t, "bot", d.Attributes["webhook.0.user"].New)
+	}
+}
+
+func TestResourceActionsConnectorUpdate_secrets(t *testing.T) {
+	var body string
+	fail := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			b, _ := ioutil.ReadAll(r.Body)
+			body = string(b)
+			if fail {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+		fmt.Fprint(w, `{"id": "c55b6eb0", "name": "my-renamed-connector", "connector_type_id": ".slack", "config": {}}`)
+	}))
+	defer ts.Close()
+
+	r := resourceActionsConnector()
+	state := &terraform.InstanceState{
+		ID: "c55b6eb0",
+		Attributes: map[string]string{
+			"id":                "c55b6eb0",
+			"name":              "my-connector",
+			"connector_type_id": ".slack",
+			"config":            "{}",
+			"secrets":           hashcode.Salted("5a17", `{"webhookUrl":"https://hooks.slack.com/services/T000/B000/XXXX"}`),
+			"secrets_salt":      "5a17",
+			"space_id":          "testSpace",
+		},
+	}
+	newData := func() *schema.ResourceData {
+		diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":              "my-renamed-connector",
+			"connector_type_id": ".slack",
+			"config":            "{}",
+			"secrets":           `{"webhookUrl": "https://hooks.slack.com/services/T000/B000/XXXX"}`,
+		}), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := schema.InternalMap(r.Schema).Data(state, diff)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	d := newData()
+	diags := resourceActionsConnectorUpdate(context.Background(), d, testClient(t, ts))
+	assert.Empty(t, diags)
+	assert.Contains(t, body, "https://hooks.slack.com/services/T000/B000/XXXX")
+	assert.Equal(t, state.Attributes["secrets"], d.State().Attributes["secrets"])
+
+	// a failed update keeps the previous state, without the cleartext secrets
+	fail = true
+	d = newData()
+	diags = resourceActionsConnectorUpdate(context.Background(), d, testClient(t, ts))
+	assert.True(t, diags.HasError())
+	assert.Equal(t, state.Attributes["secrets"], d.State().Attributes["secrets"])
+	assert.Equal(t, "my-connector", d.State().Attributes["name"])
+}
+
+func TestResourceActionsConnectorCustomizeDiff(t *testing.T) {
+	fail := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/s/testSpace/api/actions/connector_types", r.URL.Path)
+		if fail {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `[
+			{"id": ".index", "enabled": true, "enabled_in_license": true, "minimum_license_required": "basic"},
+			{"id": ".jira", "enabled": false, "enabled_in_license": false, "minimum_license_required": "gold"},
+			{"id": ".slack", "enabled": true, "enabled_in_license": true, "minimum_license_required": "gold"},
+			{"id": ".teams", "enabled": false, "enabled_in_license": true, "minimum_license_required": "gold"}
+		]`)
+	}))
+	defer ts.Close()
+
+	state := &terraform.InstanceState{
+		ID: "c55b6eb0",
+		Attributes: map[string]string{
+			"id":                "c55b6eb0",
+			"name":              "my-connector",
+			"connector_type_id": ".index",
+			"config":            `{"index":"test-index"}`,
+			"space_id":          "testSpace",
+		},
+	}
+
+	diff := func(connectorTypeID string) (*terraform.InstanceDiff, error) {
+		return resourceActionsConnector().Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":              "my-connector",
+			"connector_type_id": connectorTypeID,
+			"config":            `{"index":"test-index"}`,
+		}), testClient(t, ts))
+	}
+
+	d, err := diff(".slack")
+	if assert.NoError(t, err) && assert.NotNil(t, d) {
+		assert.True(t, d.RequiresNew())
+	}
+
+	_, err = diff(".unknown")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `connector type ".unknown" is not offered by the server`)
+		assert.Contains(t, err.Error(), ".index, .jira, .slack, .teams")
+	}
+
+	_, err = diff(".jira")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `connector type ".jira" is not enabled by the license of the server, it requires a gold license`)
+	}
+
+	_, err = diff(".teams")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `connector type ".teams" is not enabled by the server`)
+	}
+
+	fail = true
+	_, err = diff(".slack")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `unable to retrieve the connector types to validate ".slack"`)
+	}
 }
 
 func testAccCheckKibanaActionsConnectorExists(resourceName string) resource.TestCheckFunc {
@@ -39,7 +355,7 @@ func testAccCheckKibanaActionsConnectorExists(resourceName string) resource.Test
 			return fmt.Errorf("no ID is set")
 		}
 
-		c := testAccProvider.Meta().(*gk.Client)
+		c := testAccProvider.Meta().(*apiClient)
 		_, err := c.GetConnector(rs.Primary.ID)
 		if err != nil {
 			return err
@@ -65,8 +381,20 @@ func testAccKibanaActionsConnector(resourceName string) string {
 			}`, resourceName, resourceName)
 }
 
+func testAccKibanaActionsConnectorTyped(resourceName string) string {
+	return fmt.Sprintf(`
+			resource "kibana_actions_connector" "%s" {
+			  name = "test_connector_%s"
+
+			  index {
+				index   = "test-index"
+				refresh = true
+			  }
+			}`, resourceName, resourceName)
+}
+
 func testAccCheckKibanaActionsConnectorDestroy(s *terraform.State) error {
-	c := testAccProvider.Meta().(*gk.Client)
+	c := testAccProvider.Meta().(*apiClient)
 
 	// loop through the resources in state, verifying each widget
 	// is destroyed
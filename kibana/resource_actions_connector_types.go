@@ -0,0 +1,229 @@
+package kibana
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// connectorField - attribute of a typed connector block, mapped to a key of
+// the connector config or secrets
+type connectorField struct {
+	key    string
+	secret bool
+	schema *schema.Schema
+}
+
+// connectorType - typed connector block for a connector type
+type connectorType struct {
+	typeID string
+	fields map[string]connectorField
+}
+
+// connectorTypes - typed connector blocks by block name
+var connectorTypes = map[string]connectorType{
+	"email": {
+		typeID: ".email",
+		fields: map[string]connectorField{
+			"from":     {key: "from", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+			"has_auth": {key: "hasAuth", schema: &schema.Schema{Type: schema.TypeBool, Optional: true, Default: true}},
+			"host":     {key: "host", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+			"password": {key: "password", secret: true, schema: &schema.Schema{Type: schema.TypeString, Optional: true, Sensitive: true}},
+			"port":     {key: "port", schema: &schema.Schema{Type: schema.TypeInt, Optional: true}},
+			"secure":   {key: "secure", schema: &schema.Schema{Type: schema.TypeBool, Optional: true}},
+			"service":  {key: "service", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+			"user":     {key: "user", secret: true, schema: &schema.Schema{Type: schema.TypeString, Optional: true, Sensitive: true}},
+		},
+	},
+	"index": {
+		typeID: ".index",
+		fields: map[string]connectorField{
+			"execution_time_field": {key: "executionTimeField", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+			"index":                {key: "index", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+			"refresh":              {key: "refresh", schema: &schema.Schema{Type: schema.TypeBool, Optional: true, Default: false}},
+		},
+	},
+	"jira": {
+		typeID: ".jira",
+		fields: map[string]connectorField{
+			"api_token":   {key: "apiToken", secret: true, schema: &schema.Schema{Type: schema.TypeString, Required: true, Sensitive: true}},
+			"api_url":     {key: "apiUrl", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+			"email":       {key: "email", secret: true, schema: &schema.Schema{Type: schema.TypeString, Required: true, Sensitive: true}},
+			"project_key": {key: "projectKey", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+		},
+	},
+	"opsgenie": {
+		typeID: ".opsgenie",
+		fields: map[string]connectorField{
+			"api_key": {key: "apiKey", secret: true, schema: &schema.Schema{Type: schema.TypeString, Required: true, Sensitive: true}},
+			"api_url": {key: "apiUrl", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+		},
+	},
+	"pagerduty": {
+		typeID: ".pagerduty",
+		fields: map[string]connectorField{
+			"api_url":     {key: "apiUrl", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+			"routing_key": {key: "routingKey", secret: true, schema: &schema.Schema{Type: schema.TypeString, Required: true, Sensitive: true}},
+		},
+	},
+	"server_log": {
+		typeID: ".server-log",
+		fields: map[string]connectorField{},
+	},
+	"servicenow": {
+		typeID: ".servicenow",
+		fields: map[string]connectorField{
+			"api_url":  {key: "apiUrl", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+			"password": {key: "password", secret: true, schema: &schema.Schema{Type: schema.TypeString, Required: true, Sensitive: true}},
+			"username": {key: "username", secret: true, schema: &schema.Schema{Type: schema.TypeString, Required: true, Sensitive: true}},
+		},
+	},
+	"slack": {
+		typeID: ".slack",
+		fields: map[string]connectorField{
+			"webhook_url": {key: "webhookUrl", secret: true, schema: &schema.Schema{Type: schema.TypeString, Required: true, Sensitive: true}},
+		},
+	},
+	"teams": {
+		typeID: ".teams",
+		fields: map[string]connectorField{
+			"webhook_url": {key: "webhookUrl", secret: true, schema: &schema.Schema{Type: schema.TypeString, Required: true, Sensitive: true}},
+		},
+	},
+	"webhook": {
+		typeID: ".webhook",
+		fields: map[string]connectorField{
+			"has_auth": {key: "hasAuth", schema: &schema.Schema{Type: schema.TypeBool, Optional: true, Default: true}},
+			"headers":  {key: "headers", schema: &schema.Schema{Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}}},
+			"method":   {key: "method", schema: &schema.Schema{Type: schema.TypeString, Optional: true, Default: "post"}},
+			"password": {key: "password", secret: true, schema: &schema.Schema{Type: schema.TypeString, Optional: true, Sensitive: true}},
+			"url":      {key: "url", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+			"user":     {key: "user", secret: true, schema: &schema.Schema{Type: schema.TypeString, Optional: true, Sensitive: true}},
+		},
+	},
+}
+
+// connectorTypeBlockNames - sorted names of the typed connector blocks
+func connectorTypeBlockNames() []string {
+	var names []string
+	for name := range connectorTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// connectorTypeSchemas - schema of the typed connector blocks, of which only
+// one, or the generic config, can be used by a connector
+func connectorTypeSchemas() map[string]*schema.Schema {
+	exactlyOneOf := append(connectorTypeBlockNames(), "config")
+
+	schemas := map[string]*schema.Schema{}
+	for name, t := range connectorTypes {
+		fields := map[string]*schema.Schema{}
+		for fieldName, field := range t.fields {
+			fieldSchema := *field.schema
+			if field.secret {
+				fieldSchema.DiffSuppressFunc = suppressUnchangedConnectorSecrets
+			}
+			fields[fieldName] = &fieldSchema
+		}
+
+		schemas[name] = &schema.Schema{
+			Type:         schema.TypeList,
+			Optional:     true,
+			MaxItems:     1,
+			ExactlyOneOf: exactlyOneOf,
+			Elem: &schema.Resource{
+				Schema: fields,
+			},
+		}
+	}
+	return schemas
+}
+
+// connectorTypeBlock - returns the typed connector block in use, if any
+func connectorTypeBlock(d interface{ Get(string) interface{} }) (string, connectorType, bool) {
+	for _, name := range connectorTypeBlockNames() {
+		if len(d.Get(name).([]interface{})) > 0 {
+			return name, connectorTypes[name], true
+		}
+	}
+	return "", connectorType{}, false
+}
+
+// expandConnectorTypeBlock - maps a typed connector block to the connector
+// config and secrets
+func expandConnectorTypeBlock(d *schema.ResourceData, name string) (map[string]interface{}, map[string]interface{}) {
+	config := map[string]interface{}{}
+	secrets := map[string]interface{}{}
+
+	block, _ := d.Get(name).([]interface{})[0].(map[string]interface{})
+	for fieldName, field := range connectorTypes[name].fields {
+		value, ok := block[fieldName]
+		if !ok || value == nil {
+			continue
+		}
+
+		// unset optional strings, numbers and maps are left for Kibana to default
+		switch v := value.(type) {
+		case string:
+			if v == "" {
+				continue
+			}
+		case int:
+			if v == 0 {
+				continue
+			}
+		case map[string]interface{}:
+			if len(v) == 0 {
+				continue
+			}
+		}
+
+		if field.secret {
+			secrets[field.key] = value
+		} else {
+			config[field.key] = value
+		}
+	}
+
+	return config, secrets
+}
+
+// flattenConnectorTypeBlock - maps the connector config to a typed connector
+// block, keeping the hashed secrets from the state as Kibana never returns them
+func flattenConnectorTypeBlock(d *schema.ResourceData, name string, config map[string]interface{}) error {
+	current := map[string]interface{}{}
+	if v := d.Get(name).([]interface{}); len(v) > 0 && v[0] != nil {
+		current = v[0].(map[string]interface{})
+	}
+
+	block := map[string]interface{}{}
+	for fieldName, field := range connectorTypes[name].fields {
+		if field.secret {
+			block[fieldName] = current[fieldName]
+			continue
+		}
+
+		value, ok := config[field.key]
+		if !ok || value == nil {
+			block[fieldName] = nil
+			continue
+		}
+
+		switch field.schema.Type {
+		case schema.TypeInt:
+			number, ok := value.(float64)
+			if !ok {
+				return fmt.Errorf("unexpected value %v for %s.%s", value, name, fieldName)
+			}
+			block[fieldName] = int(number)
+		default:
+			block[fieldName] = value
+		}
+	}
+
+	return d.Set(name, []interface{}{block})
+}
@@ -3,189 +3,306 @@ package kibana
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	gk "github.com/renato0307/go-kibana/kibana"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/renato0307/terraform-provider-kibana/kibana/internal/hashcode"
+	"github.com/renato0307/terraform-provider-kibana/kibana/internal/utils"
 )
 
+// ruleRunTimestampFormat - format of the dates returned by Kibana
+const ruleRunTimestampFormat = "2006-01-02T15:04:05.000Z07:00"
+
 func resourceAlertingRule() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceAlertingRuleCreate,
-		ReadContext:   resourceAlertingRuleRead,
-		UpdateContext: resourceAlertingRuleUpdate,
-		DeleteContext: resourceAlertingRuleDelete,
-		Schema: map[string]*schema.Schema{
-			"action": {
-				Type:     schema.TypeSet,
-				Required: true,
-				Computed: false,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"id": {
-							Type:     schema.TypeString,
-							Required: true,
-							Computed: false,
-						},
-						"group": {
-							Type:     schema.TypeString,
-							Required: true,
-							Computed: false,
-						},
-						"params": {
-							Type:     schema.TypeString,
-							Required: true,
-							Computed: false,
+	resourceSchema := map[string]*schema.Schema{
+		"action": {
+			Type:     schema.TypeSet,
+			Required: true,
+			Computed: false,
+			Set:      resourceAlertingRuleActionHash,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"id": {
+						Type:     schema.TypeString,
+						Required: true,
+						Computed: false,
+					},
+					"group": {
+						Type:     schema.TypeString,
+						Required: true,
+						Computed: false,
+					},
+					"params": {
+						Type:             schema.TypeString,
+						Required:         true,
+						Computed:         false,
+						ValidateFunc:     validation.StringIsJSON,
+						DiffSuppressFunc: utils.SuppressEquivalentJSONDiffs,
+					},
+					"frequency": {
+						Type:     schema.TypeList,
+						Optional: true,
+						MaxItems: 1,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"summary": {
+									Type:     schema.TypeBool,
+									Required: true,
+								},
+								"notify_when": {
+									Type:         schema.TypeString,
+									Required:     true,
+									ValidateFunc: validation.StringInSlice([]string{"onActionGroupChange", "onActiveAlert", "onThrottleInterval"}, false),
+								},
+								"throttle": {
+									Type:     schema.TypeString,
+									Optional: true,
+								},
+							},
 						},
 					},
 				},
 			},
-			"api_key_owner": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"consumer": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"created_at": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"created_by": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"enabled": {
-				Type:     schema.TypeBool,
-				Optional: true,
-			},
-			"last_execution_date": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"last_execution_status": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"id": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"mute_all": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
-			"muted_alert_ids": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-			},
-			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"notify_when": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"param_agg_field": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"param_agg_type": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"param_es_query": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"param_group_by": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"param_index": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-			},
-			"param_size": {
-				Type:     schema.TypeInt,
-				Optional: true,
-			},
-			"param_term_field": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"param_term_size": {
-				Type:     schema.TypeInt,
-				Optional: true,
-			},
-			"param_threshold": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeInt,
-				},
-			},
-			"param_threshold_comparator": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"param_time_field": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"param_time_window_size": {
-				Type:     schema.TypeInt,
-				Optional: true,
-			},
-			"param_time_window_unit": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"rule_type_id": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"schedule_interval": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"scheduled_task_id": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"tags": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
+		},
+		"api_key_owner": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"api_key_rotation_trigger": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"consumer": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"created_at": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"created_by": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"enabled": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		"is_snoozed_until": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"fail_on_first_run_error": {
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+		"last_execution_date": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"last_execution_duration": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"last_execution_error_message": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"last_execution_error_reason": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"last_execution_status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"last_execution_warning_message": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"last_execution_warning_reason": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"monitoring_run_history": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"duration": {
+						Type:     schema.TypeInt,
+						Computed: true,
+					},
+					"outcome": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"success": {
+						Type:     schema.TypeBool,
+						Computed: true,
+					},
+					"timestamp": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
 				},
 			},
-			"throttle": {
-				Type:     schema.TypeString,
-				Optional: true,
+		},
+		"monitoring_success_ratio": {
+			Type:     schema.TypeFloat,
+			Computed: true,
+		},
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"mute_all": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"muted_alert_ids": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
 			},
-			"updated_at": {
-				Type:     schema.TypeString,
-				Computed: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"notify_when": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"rule_type_id": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			Computed:      true,
+			ForceNew:      true,
+			ConflictsWith: ruleParamsBlockNames(),
+		},
+		"schedule_interval": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"scheduled_task_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"snooze_schedule": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			Set:      resourceAlertingRuleSnoozeScheduleHash,
+			Elem: &schema.Resource{
+				Schema: ruleSnoozeScheduleSchema(),
 			},
-			"updated_by": {
-				Type:     schema.TypeString,
-				Computed: true,
+		},
+		"space_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			ForceNew: true,
+		},
+		"tags": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
 			},
 		},
+		"throttle": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"updated_at": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"updated_by": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+
+	// adds the generic params, the typed rule params blocks and the param_*
+	// attributes
+	for name, paramsSchema := range ruleParamsSchemas() {
+		resourceSchema[name] = paramsSchema
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceAlertingRuleCreate,
+		ReadContext:   resourceAlertingRuleRead,
+		UpdateContext: resourceAlertingRuleUpdate,
+		DeleteContext: resourceAlertingRuleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceImportSpaceObject,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+		},
+		CustomizeDiff: customdiff.All(
+			validateAttributeVersions(map[string]serverVersion{
+				"notify_when":     versionRuleNotifyWhen,
+				"snooze_schedule": versionRuleSnoozeSchedule,
+			}),
+			resourceAlertingRuleCustomizeDiff,
+			resourceAlertingRuleActionsCustomizeDiff,
+		),
+		Schema: resourceSchema,
 	}
 }
 
+// resourceAlertingRuleCustomizeDiff - sets the rule type of typed rule params
+// blocks and requires it for the param_* attributes
+func resourceAlertingRuleCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if _, t, ok := ruleParamsBlock(d); ok {
+		if d.Get("rule_type_id").(string) != t.ruleTypeID {
+			return d.SetNew("rule_type_id", t.ruleTypeID)
+		}
+		return nil
+	}
+
+	if d.Get("rule_type_id").(string) == "" && d.NewValueKnown("rule_type_id") {
+		return fmt.Errorf("rule_type_id is required when not using a typed params block")
+	}
+	return nil
+}
+
+// resourceAlertingRuleActionsCustomizeDiff - checks that the server supports
+// the frequency of actions, which cannot be combined with the notify_when and
+// throttle of the rule
+func resourceAlertingRuleActionsCustomizeDiff(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	usesFrequency := false
+	for _, v := range d.Get("action").(*schema.Set).List() {
+		if frequency, ok := v.(map[string]interface{})["frequency"].([]interface{}); ok && len(frequency) > 0 {
+			usesFrequency = true
+		}
+	}
+	if !usesFrequency {
+		return nil
+	}
+
+	if c, ok := m.(*apiClient); ok && !c.supports(versionActionFrequency) {
+		return fmt.Errorf("action.frequency requires Kibana %s or later, but the server is running %s", versionActionFrequency, c.version)
+	}
+
+	if d.Get("notify_when").(string) != "" || d.Get("throttle").(string) != "" {
+		return fmt.Errorf("notify_when and throttle cannot be set when an action uses frequency")
+	}
+	return nil
+}
+
 // resourceAlertingRuleCreate - creates an alerting Rule
 func resourceAlertingRuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
@@ -196,13 +313,34 @@ func resourceAlertingRuleCreate(ctx context.Context, d *schema.ResourceData, m i
 	}
 
 	// calls API to create the rule
-	c := m.(*gk.Client)
+	c := resourceClient(d, m)
 	newRule, err := c.CreateRule(rule)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	d.SetId(newRule.ID)
 
+	// mutes the rule or its alerts
+	err = updateRuleMutes(d, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// snoozes the rule
+	err = updateRuleSnoozeSchedules(d, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// waits for the first run, which starts right after the rule is created. A
+	// run ending in error leaves the rule tainted, to be replaced on next apply
+	if checkRuleFirstRun(d) {
+		err = waitForRuleRun(ctx, c, d.Id(), newRule.ExecutionStatus.LastExecutionDate, d.Timeout(schema.TimeoutCreate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	// reads the created rule
 	return resourceAlertingRuleRead(ctx, d, m)
 }
@@ -213,8 +351,11 @@ func resourceAlertingRuleRead(_ context.Context, d *schema.ResourceData, m inter
 	ruleID := d.Id()
 
 	// reads the rule from Kibana
-	c := m.(*gk.Client)
+	c := resourceClient(d, m)
 	rule, err := c.GetRule(ruleID)
+	if isNotFound(err) {
+		return resourceNotFound(d, "rule")
+	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -224,33 +365,87 @@ func resourceAlertingRuleRead(_ context.Context, d *schema.ResourceData, m inter
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	
+	_ = d.Set("space_id", c.Space)
+
 	return nil
 }
 
 // resourceAlertingRuleUpdate - updates an alerting rule
 func resourceAlertingRuleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	// maps the resource data to an RuleUpdate struct
+	var diags diag.Diagnostics
 	ruleID := d.Id()
-	rule, err := expandRuleUpdate(d)
+	c := resourceClient(d, m)
+
+	// updates the rule, unless only its state, mutes, snoozes or API key
+	// changed
+	if d.HasChangesExcept("api_key_rotation_trigger", "enabled", "mute_all", "muted_alert_ids", "snooze_schedule") {
+		// maps the resource data to an RuleUpdate struct
+		rule, err := expandRuleUpdate(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		// calls API to update the rule
+		updatedRule, err := c.UpdateRule(ruleID, rule)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		// sets common fields
+		d.SetId(updatedRule.ID)
+		_ = d.Set("last_updated", time.Now().Format(time.RFC850))
+
+		// runs the updated rule and waits for the run, as without it the next
+		// run only happens at the rule interval
+		if checkRuleFirstRun(d) {
+			err = c.runRuleSoon(ruleID)
+			if err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  fmt.Sprintf("The first run of rule %s was not checked", ruleID),
+					Detail:   fmt.Sprintf("Unable to run the updated rule: %s", err),
+				})
+			} else {
+				err = waitForRuleRun(ctx, c, ruleID, updatedRule.ExecutionStatus.LastExecutionDate, d.Timeout(schema.TimeoutUpdate))
+				if err != nil {
+					return diag.FromErr(err)
+				}
+			}
+		}
+	}
+
+	// rotates the API key, which the rule then runs with
+	if d.HasChange("api_key_rotation_trigger") {
+		err := c.updateRuleAPIKey(ruleID)
+		if err != nil {
+			// keeps the previous trigger, so the rotation is retried
+			d.Partial(true)
+			return diag.FromErr(err)
+		}
+	}
+
+	// enables or disables the rule
+	o, _ := d.GetChange("enabled")
+	err := updateRuleEnabled(d, c, o.(bool))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	// calls API to update the rule
-	c := m.(*gk.Client)
-	updatedRule, err := c.UpdateRule(ruleID, rule)
+	// mutes or unmutes the rule or its alerts
+	err = updateRuleMutes(d, c)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	// sets common fields
-	d.SetId(updatedRule.ID)
-	_ = d.Set("last_updated", time.Now().Format(time.RFC850))
+	// snoozes or unsnoozes the rule
+	err = updateRuleSnoozeSchedules(d, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// reads the updated rule and returns
-	return resourceAlertingRuleRead(ctx, d, m)
+	return append(diags, resourceAlertingRuleRead(ctx, d, m)...)
 }
 
 // resourceAlertingRuleDelete - deletes an alerting rule
@@ -258,9 +453,9 @@ func resourceAlertingRuleDelete(_ context.Context, d *schema.ResourceData, m int
 
 	ruleID := d.Id()
 
-	c := m.(*gk.Client)
+	c := resourceClient(d, m)
 	err := c.DeleteRule(ruleID)
-	if err != nil {
+	if err != nil && !isNotFound(err) {
 		return diag.FromErr(err)
 	}
 
@@ -270,10 +465,156 @@ func resourceAlertingRuleDelete(_ context.Context, d *schema.ResourceData, m int
 	return nil
 }
 
+// updateRuleEnabled - enables or disables a rule when its current state
+// differs from the configuration
+func updateRuleEnabled(d *schema.ResourceData, c *apiClient, enabled bool) error {
+	if d.Get("enabled").(bool) == enabled {
+		return nil
+	}
+
+	if d.Get("enabled").(bool) {
+		return c.enableRule(d.Id())
+	}
+	return c.disableRule(d.Id())
+}
+
+// updateRuleMutes - mutes or unmutes all alerts of a rule and each alert in
+// muted_alert_ids to match the configuration
+func updateRuleMutes(d *schema.ResourceData, c *apiClient) error {
+	ruleID := d.Id()
+
+	if d.HasChange("mute_all") {
+		var err error
+		if d.Get("mute_all").(bool) {
+			err = c.muteAllRule(ruleID)
+		} else {
+			err = c.unmuteAllRule(ruleID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("muted_alert_ids") {
+		o, n := d.GetChange("muted_alert_ids")
+		oldIDs, newIDs := o.(*schema.Set), n.(*schema.Set)
+
+		for _, alertID := range expandStringSet(oldIDs.Difference(newIDs)) {
+			err := c.unmuteAlert(ruleID, alertID)
+			if err != nil {
+				return err
+			}
+		}
+		for _, alertID := range expandStringSet(newIDs.Difference(oldIDs)) {
+			err := c.muteAlert(ruleID, alertID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// updateRuleSnoozeSchedules - removes the snooze schedules of a rule that are
+// no longer configured and adds the new ones
+func updateRuleSnoozeSchedules(d *schema.ResourceData, c *apiClient) error {
+	if !d.HasChange("snooze_schedule") {
+		return nil
+	}
+
+	o, n := d.GetChange("snooze_schedule")
+	oldSchedules, newSchedules := o.(*schema.Set), n.(*schema.Set)
+
+	scheduleIDs := []string{}
+	for _, s := range oldSchedules.Difference(newSchedules).List() {
+		if id := s.(map[string]interface{})["id"].(string); id != "" {
+			scheduleIDs = append(scheduleIDs, id)
+		}
+	}
+	if len(scheduleIDs) > 0 {
+		err := c.unsnoozeRule(d.Id(), scheduleIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, s := range newSchedules.Difference(oldSchedules).List() {
+		schedule, err := expandRuleSnoozeSchedule(s.(map[string]interface{}))
+		if err != nil {
+			return err
+		}
+
+		err = c.snoozeRule(d.Id(), schedule)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkRuleFirstRun - checks if the first run of a new or updated rule must be
+// waited for, which only happens for enabled rules
+func checkRuleFirstRun(d *schema.ResourceData) bool {
+	return d.Get("fail_on_first_run_error").(bool) && d.Get("enabled").(bool)
+}
+
+// waitForRuleRun - waits for a run of a rule after its previous run and fails
+// if that run ends in error
+func waitForRuleRun(ctx context.Context, c *apiClient, ruleID string, previousRun string, timeout time.Duration) error {
+	var status ruleExecutionStatus
+	err := resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		r, err := c.GetRule(ruleID)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		status = r.ExecutionStatus
+		if status.Status == "pending" || status.LastExecutionDate == previousRun {
+			return resource.RetryableError(fmt.Errorf("rule %s has not run yet", ruleID))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if status.Status == "error" {
+		if status.Error != nil {
+			return fmt.Errorf("the first run of rule %s ended in error: %s: %s", ruleID, status.Error.Reason, status.Error.Message)
+		}
+		return fmt.Errorf("the first run of rule %s ended in error", ruleID)
+	}
+	return nil
+}
+
+// ruleSnoozeScheduleSchema - schema of a snooze schedule of a rule
+//
+// The set hash already matches equivalent schedules, so the attributes do not
+// suppress diffs, which would break the removal of set elements.
+func ruleSnoozeScheduleSchema() map[string]*schema.Schema {
+	s := scheduleSchema()
+	for _, attribute := range s {
+		attribute.DiffSuppressFunc = nil
+	}
+	s["id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+	return s
+}
+
+// resourceAlertingRuleSnoozeScheduleHash - hashes a snooze schedule without its
+// ID, which is only known once Kibana creates it
+func resourceAlertingRuleSnoozeScheduleHash(v interface{}) int {
+	return hashcode.String(scheduleHashKey(v.(map[string]interface{})))
+}
+
 // Expand and flatten functions
 
 // flattenRule - fills the resource data from a Rule
-func flattenRule(d *schema.ResourceData, rule *gk.Rule) error {
+func flattenRule(d *schema.ResourceData, rule *rule) error {
 
 	if len(rule.Actions) > 0 {
 		actions, err := flattenRuleActions(rule)
@@ -286,32 +627,35 @@ func flattenRule(d *schema.ResourceData, rule *gk.Rule) error {
 		}
 	}
 
+	err := flattenRuleParams(d, rule.RuleTypeID, rule.Params)
+	if err != nil {
+		return err
+	}
+
 	_ = d.Set("api_key_owner", rule.ApiKeyOwner)
 	_ = d.Set("consumer", rule.Consumer)
 	_ = d.Set("created_at", rule.CreatedAt)
 	_ = d.Set("created_by", rule.CreatedBy)
 	_ = d.Set("enabled", rule.Enabled)
+	_ = d.Set("is_snoozed_until", rule.IsSnoozedUntil)
 	_ = d.Set("last_execution_date", rule.ExecutionStatus.LastExecutionDate)
+	_ = d.Set("last_execution_duration", rule.ExecutionStatus.LastDuration)
 	_ = d.Set("last_execution_status", rule.ExecutionStatus.Status)
+	flattenRuleExecutionMessage(d, "last_execution_error", rule.ExecutionStatus.Error)
+	flattenRuleExecutionMessage(d, "last_execution_warning", rule.ExecutionStatus.Warning)
+	flattenRuleMonitoring(d, rule.Monitoring)
 	_ = d.Set("id", rule.ID)
 	_ = d.Set("mute_all", rule.MuteAll)
 	_ = d.Set("muted_alert_ids", rule.MutedAlertIDs)
 	_ = d.Set("name", rule.Name)
-	_ = d.Set("notify_when", rule.NotifyWhen)
-	_ = d.Set("param_agg_field", rule.Params.AggField)
-	_ = d.Set("param_agg_type", rule.Params.AggType)
-	_ = d.Set("param_es_query", rule.Params.ESQuery)
-	_ = d.Set("param_group_by", rule.Params.GroupBy)
-	_ = d.Set("param_index", rule.Params.Index)
-	_ = d.Set("param_term_field", rule.Params.TermField)
-	_ = d.Set("param_term_size", rule.Params.TermSize)
-	_ = d.Set("param_threshold", rule.Params.Threshold)
-	_ = d.Set("param_time_field", rule.Params.TimeField)
-	_ = d.Set("param_time_window_size", rule.Params.TimeWindowSize)
-	_ = d.Set("param_time_window_unit", rule.Params.TimeWindowUnit)
+	// Kibana stores onActiveAlert when the notify_when is not configured
+	if d.Get("notify_when").(string) != "" {
+		_ = d.Set("notify_when", rule.NotifyWhen)
+	}
 	_ = d.Set("rule_type_id", rule.RuleTypeID)
 	_ = d.Set("schedule_interval", rule.Schedule.Interval)
-	_ = d.Set("scheduled_task_id", rule.ScheduledTaskId)
+	_ = d.Set("scheduled_task_id", rule.ScheduledTaskID)
+	_ = d.Set("snooze_schedule", flattenRuleSnoozeSchedules(rule.SnoozeSchedule, d.Get("snooze_schedule").(*schema.Set)))
 	_ = d.Set("tags", rule.Tags)
 	_ = d.Set("throttle", rule.Throttle)
 	_ = d.Set("updated_at", rule.UpdatedAt)
@@ -320,7 +664,96 @@ func flattenRule(d *schema.ResourceData, rule *gk.Rule) error {
 	return nil
 }
 
-func flattenRuleActions(rule *gk.Rule) ([]interface{}, error) {
+// flattenRuleExecutionMessage - sets the <prefix>_reason and <prefix>_message
+// attributes from an error or warning of the last run
+func flattenRuleExecutionMessage(d *schema.ResourceData, prefix string, m *ruleExecutionMessage) {
+	if m == nil {
+		m = &ruleExecutionMessage{}
+	}
+	_ = d.Set(prefix+"_reason", m.Reason)
+	_ = d.Set(prefix+"_message", m.Message)
+}
+
+// flattenRuleMonitoring - sets the run history and success ratio of a rule,
+// only returned by Kibana 8.4 or later
+func flattenRuleMonitoring(d *schema.ResourceData, monitoring *ruleMonitoring) {
+	if monitoring == nil {
+		monitoring = &ruleMonitoring{}
+	}
+
+	history := []interface{}{}
+	for _, run := range monitoring.Run.History {
+		history = append(history, map[string]interface{}{
+			"duration":  run.Duration,
+			"outcome":   run.Outcome,
+			"success":   run.Success,
+			"timestamp": time.Unix(0, run.Timestamp*int64(time.Millisecond)).UTC().Format(ruleRunTimestampFormat),
+		})
+	}
+	_ = d.Set("monitoring_run_history", history)
+	_ = d.Set("monitoring_success_ratio", monitoring.Run.CalculatedMetrics.SuccessRatio)
+}
+
+// expandRuleSnoozeSchedule - maps a snooze_schedule block to a snooze schedule
+func expandRuleSnoozeSchedule(m map[string]interface{}) (ruleSnoozeSchedule, error) {
+	duration, r, err := expandSchedule(m)
+	if err != nil {
+		return ruleSnoozeSchedule{}, err
+	}
+
+	return ruleSnoozeSchedule{Duration: duration, RRule: r}, nil
+}
+
+// flattenRuleSnoozeSchedules - maps the snooze schedules of a rule to
+// snooze_schedule blocks
+//
+// Snoozes started from the Kibana UI without a schedule have no ID and are
+// left out, as they cannot be removed by ID. Schedules equivalent to a prior
+// one keep its values, so formatting differences do not show as changes.
+func flattenRuleSnoozeSchedules(schedules []ruleSnoozeSchedule, prior *schema.Set) []interface{} {
+	priorSchedules := map[string]map[string]interface{}{}
+	for _, p := range prior.List() {
+		m := p.(map[string]interface{})
+		priorSchedules[scheduleHashKey(m)] = m
+	}
+
+	snoozeSchedules := []interface{}{}
+	for _, s := range schedules {
+		if s.ID == "" {
+			continue
+		}
+
+		m := flattenSchedule(s.Duration, s.RRule)
+		if p, ok := priorSchedules[scheduleHashKey(m)]; ok {
+			for k, v := range p {
+				m[k] = v
+			}
+		}
+		m["id"] = s.ID
+		snoozeSchedules = append(snoozeSchedules, m)
+	}
+	return snoozeSchedules
+}
+
+// resourceAlertingRuleActionHash - hashes an action ignoring the formatting of its params
+func resourceAlertingRuleActionHash(v interface{}) int {
+	m := v.(map[string]interface{})
+
+	params := m["params"].(string)
+	if normalized, err := utils.NormalizeJSON(params); err == nil {
+		params = normalized
+	}
+
+	frequency := ""
+	if v, ok := m["frequency"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+		f := v[0].(map[string]interface{})
+		frequency = fmt.Sprintf("%v-%s-%s", f["summary"], f["notify_when"], f["throttle"])
+	}
+
+	return hashcode.String(fmt.Sprintf("%s-%s-%s-%s", m["id"], m["group"], params, frequency))
+}
+
+func flattenRuleActions(rule *rule) ([]interface{}, error) {
 	log.Printf("flattenRule - number of actions found: %d", len(rule.Actions))
 	var actions []interface{}
 	for _, a := range rule.Actions {
@@ -335,41 +768,67 @@ func flattenRuleActions(rule *gk.Rule) ([]interface{}, error) {
 			"group":  a.Group,
 			"params": paramsString,
 		}
+		if a.Frequency != nil {
+			throttle := ""
+			if a.Frequency.Throttle != nil {
+				throttle = *a.Frequency.Throttle
+			}
+			m["frequency"] = []interface{}{
+				map[string]interface{}{
+					"summary":     a.Frequency.Summary,
+					"notify_when": a.Frequency.NotifyWhen,
+					"throttle":    throttle,
+				},
+			}
+		}
 		actions = append(actions, m)
 	}
 	return actions, nil
 }
 
-func expandRuleUpdate(d *schema.ResourceData) (gk.UpdateRule, error) {
-	// sets rule actions
-	var actions []gk.RuleAction
+// expandRuleActions - maps the action blocks to the rule actions
+func expandRuleActions(d *schema.ResourceData) ([]ruleAction, error) {
+	var actions []ruleAction
 	if v, ok := d.GetOk("action"); ok && v.(*schema.Set).Len() > 0 {
 		for _, v := range v.(*schema.Set).List() {
 			v := v.(map[string]interface{})
 			var b map[string]interface{}
 			err := json.Unmarshal([]byte(v["params"].(string)), &b)
 			if err != nil {
-				return gk.UpdateRule{}, err
+				return nil, err
 			}
-			action := gk.RuleAction{
+			action := ruleAction{
 				ID:     v["id"].(string),
 				Group:  v["group"].(string),
 				Params: b,
 			}
+			if frequency, ok := v["frequency"].([]interface{}); ok && len(frequency) > 0 && frequency[0] != nil {
+				f := frequency[0].(map[string]interface{})
+				action.Frequency = &ruleActionFrequency{
+					Summary:    f["summary"].(bool),
+					NotifyWhen: f["notify_when"].(string),
+				}
+				if throttle := f["throttle"].(string); throttle != "" {
+					action.Frequency.Throttle = &throttle
+				}
+			}
 			actions = append(actions, action)
 		}
 	}
+	return actions, nil
+}
 
-	// sets the Params.Index
-	var paramsIndexSlice []string
-	for _, param := range d.Get("param_index").([]interface{}) {
-		paramsIndexSlice = append(paramsIndexSlice, param.(string))
+func expandRuleUpdate(d *schema.ResourceData) (ruleUpdate, error) {
+	// sets rule actions
+	actions, err := expandRuleActions(d)
+	if err != nil {
+		return ruleUpdate{}, err
 	}
 
-	// sets the Params.Threshold
-	var paramsThresholdSlice []int
-	for _, param := range d.Get("param_threshold").([]interface{}) {
-		paramsThresholdSlice = append(paramsThresholdSlice, param.(int))
+	// sets rule params
+	params, err := expandRuleParams(d)
+	if err != nil {
+		return ruleUpdate{}, err
 	}
 
 	// sets the Tags
@@ -379,62 +838,32 @@ func expandRuleUpdate(d *schema.ResourceData) (gk.UpdateRule, error) {
 	}
 
 	// sets the rest of the fields for the rule
-	rule := gk.UpdateRule{
+	rule := ruleUpdate{
 		Actions:    actions,
 		Name:       d.Get("name").(string),
 		NotifyWhen: d.Get("notify_when").(string),
-		Params: gk.RuleParams{
-			AggField:            d.Get("param_agg_field").(string),
-			AggType:             d.Get("param_agg_type").(string),
-			ESQuery:             d.Get("param_es_query").(string),
-			GroupBy:             d.Get("param_group_by").(string),
-			Index:               paramsIndexSlice,
-			Size:                d.Get("param_size").(int),
-			TermField:           d.Get("param_term_field").(string),
-			TermSize:            d.Get("param_term_size").(int),
-			Threshold:           paramsThresholdSlice,
-			ThresholdComparator: d.Get("param_threshold_comparator").(string),
-			TimeField:           d.Get("param_time_field").(string),
-			TimeWindowSize:      d.Get("param_time_window_size").(int),
-			TimeWindowUnit:      d.Get("param_time_window_unit").(string),
-		},
-		Throttle: d.Get("throttle").(string),
-		Schedule: gk.RuleSchedule{Interval: d.Get("schedule_interval").(string)},
-		Tags:     tagsSlice,
+		Params:     params,
+		Throttle:   d.Get("throttle").(string),
+		Schedule:   ruleSchedule{Interval: d.Get("schedule_interval").(string)},
+		Tags:       tagsSlice,
 	}
 	return rule, nil
 }
 
-func expandCreateRule(d *schema.ResourceData) (gk.CreateRule, error) {
+func expandCreateRule(d *schema.ResourceData) (ruleCreate, error) {
 	// sets rule actions
-	var actions []gk.RuleAction
-	if v, ok := d.GetOk("action"); ok && v.(*schema.Set).Len() > 0 {
-		for _, v := range v.(*schema.Set).List() {
-			v := v.(map[string]interface{})
-			var b map[string]interface{}
-			err := json.Unmarshal([]byte(v["params"].(string)), &b)
-			if err != nil {
-				return gk.CreateRule{}, err
-			}
-			action := gk.RuleAction{
-				ID:     v["id"].(string),
-				Group:  v["group"].(string),
-				Params: b,
-			}
-			actions = append(actions, action)
-		}
+	actions, err := expandRuleActions(d)
+	if err != nil {
+		return ruleCreate{}, err
 	}
 
-	// sets the Params.Index
-	var paramsIndexSlice []string
-	for _, param := range d.Get("param_index").([]interface{}) {
-		paramsIndexSlice = append(paramsIndexSlice, param.(string))
+	// sets rule params
+	params, err := expandRuleParams(d)
+	if err != nil {
+		return ruleCreate{}, err
 	}
-
-	// sets the Params.Threshold
-	var paramsThresholdSlice []int
-	for _, param := range d.Get("param_threshold").([]interface{}) {
-		paramsThresholdSlice = append(paramsThresholdSlice, param.(int))
+	if d.Get("rule_type_id").(string) == "" {
+		return ruleCreate{}, fmt.Errorf("rule_type_id is required when not using a typed params block")
 	}
 
 	// sets the Tags
@@ -444,29 +873,17 @@ func expandCreateRule(d *schema.ResourceData) (gk.CreateRule, error) {
 	}
 
 	// sets the rest of the fields for the rule
-	rule := gk.CreateRule{
+	rule := ruleCreate{
 		Actions:    actions,
 		Consumer:   d.Get("consumer").(string),
+		Enabled:    d.Get("enabled").(bool),
 		Name:       d.Get("name").(string),
 		NotifyWhen: d.Get("notify_when").(string),
-		Params: gk.RuleParams{
-			AggField:            d.Get("param_agg_field").(string),
-			AggType:             d.Get("param_agg_type").(string),
-			ESQuery:             d.Get("param_es_query").(string),
-			GroupBy:             d.Get("param_group_by").(string),
-			Index:               paramsIndexSlice,
-			Size:                d.Get("param_size").(int),
-			TermField:           d.Get("param_term_field").(string),
-			TermSize:            d.Get("param_term_size").(int),
-			Threshold:           paramsThresholdSlice,
-			ThresholdComparator: d.Get("param_threshold_comparator").(string),
-			TimeField:           d.Get("param_time_field").(string),
-			TimeWindowSize:      d.Get("param_time_window_size").(int),
-			TimeWindowUnit:      d.Get("param_time_window_unit").(string),
-		},
+		Params:     params,
 		RuleTypeID: d.Get("rule_type_id").(string),
-		Schedule:   gk.RuleSchedule{Interval: d.Get("schedule_interval").(string)},
+		Schedule:   ruleSchedule{Interval: d.Get("schedule_interval").(string)},
 		Tags:       tagsSlice,
+		Throttle:   d.Get("throttle").(string),
 	}
 	return rule, nil
 }
@@ -0,0 +1,531 @@
+package kibana
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/renato0307/terraform-provider-kibana/kibana/internal/utils"
+)
+
+// ruleParamsField - attribute of a typed rule params block, mapped to a key of
+// the rule params
+type ruleParamsField struct {
+	key string
+	// json is set for string attributes holding a JSON object param
+	json bool
+	// fields is set for nested blocks
+	fields map[string]ruleParamsField
+	schema *schema.Schema
+}
+
+// ruleParamsType - typed rule params block for a rule type
+type ruleParamsType struct {
+	ruleTypeID string
+	fields     map[string]ruleParamsField
+}
+
+var (
+	ruleThresholdComparators = []string{">", ">=", "<", "<=", "between", "notBetween"}
+	ruleTimeUnits            = []string{"s", "m", "h", "d"}
+)
+
+// ruleParamsTypes - typed rule params blocks by block name
+var ruleParamsTypes = map[string]ruleParamsType{
+	"apm_error_rate": {
+		ruleTypeID: "apm.error_rate",
+		fields: map[string]ruleParamsField{
+			"environment":  {key: "environment", schema: &schema.Schema{Type: schema.TypeString, Optional: true, Default: "ENVIRONMENT_ALL"}},
+			"service_name": {key: "serviceName", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+			"threshold":    {key: "threshold", schema: &schema.Schema{Type: schema.TypeInt, Required: true, ValidateFunc: validation.IntAtLeast(0)}},
+			"window_size":  {key: "windowSize", schema: &schema.Schema{Type: schema.TypeInt, Required: true, ValidateFunc: validation.IntAtLeast(1)}},
+			"window_unit":  {key: "windowUnit", schema: &schema.Schema{Type: schema.TypeString, Required: true, ValidateFunc: validation.StringInSlice(ruleTimeUnits, false)}},
+		},
+	},
+	"es_query": {
+		ruleTypeID: ".es-query",
+		fields: map[string]ruleParamsField{
+			"es_query":             {key: "esQuery", schema: &schema.Schema{Type: schema.TypeString, Required: true, ValidateFunc: validation.StringIsJSON, DiffSuppressFunc: utils.SuppressEquivalentJSONDiffs}},
+			"index":                {key: "index", schema: &schema.Schema{Type: schema.TypeList, Required: true, MinItems: 1, Elem: &schema.Schema{Type: schema.TypeString}}},
+			"size":                 {key: "size", schema: &schema.Schema{Type: schema.TypeInt, Required: true, ValidateFunc: validation.IntBetween(0, 10000)}},
+			"threshold":            {key: "threshold", schema: &schema.Schema{Type: schema.TypeList, Required: true, MinItems: 1, MaxItems: 2, Elem: &schema.Schema{Type: schema.TypeFloat}}},
+			"threshold_comparator": {key: "thresholdComparator", schema: &schema.Schema{Type: schema.TypeString, Required: true, ValidateFunc: validation.StringInSlice(ruleThresholdComparators, false)}},
+			"time_field":           {key: "timeField", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+			"time_window_size":     {key: "timeWindowSize", schema: &schema.Schema{Type: schema.TypeInt, Required: true, ValidateFunc: validation.IntAtLeast(1)}},
+			"time_window_unit":     {key: "timeWindowUnit", schema: &schema.Schema{Type: schema.TypeString, Required: true, ValidateFunc: validation.StringInSlice(ruleTimeUnits, false)}},
+		},
+	},
+	"geo_containment": {
+		ruleTypeID: ".geo-containment",
+		fields: map[string]ruleParamsField{
+			"boundary_geo_field":   {key: "boundaryGeoField", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+			"boundary_index_id":    {key: "boundaryIndexId", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+			"boundary_index_query": {key: "boundaryIndexQuery", json: true, schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+			"boundary_index_title": {key: "boundaryIndexTitle", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+			"boundary_name_field":  {key: "boundaryNameField", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+			"boundary_type":        {key: "boundaryType", schema: &schema.Schema{Type: schema.TypeString, Optional: true, Default: "entireIndex"}},
+			"date_field":           {key: "dateField", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+			"entity":               {key: "entity", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+			"geo_field":            {key: "geoField", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+			"index":                {key: "index", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+			"index_id":             {key: "indexId", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+			"index_query":          {key: "indexQuery", json: true, schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+		},
+	},
+	"index_threshold": {
+		ruleTypeID: ".index-threshold",
+		fields: map[string]ruleParamsField{
+			"agg_field":            {key: "aggField", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+			"agg_type":             {key: "aggType", schema: &schema.Schema{Type: schema.TypeString, Optional: true, Default: "count", ValidateFunc: validation.StringInSlice([]string{"count", "avg", "sum", "min", "max"}, false)}},
+			"filter_kuery":         {key: "filterKuery", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+			"group_by":             {key: "groupBy", schema: &schema.Schema{Type: schema.TypeString, Optional: true, Default: "all", ValidateFunc: validation.StringInSlice([]string{"all", "top"}, false)}},
+			"index":                {key: "index", schema: &schema.Schema{Type: schema.TypeList, Required: true, MinItems: 1, Elem: &schema.Schema{Type: schema.TypeString}}},
+			"term_field":           {key: "termField", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+			"term_size":            {key: "termSize", schema: &schema.Schema{Type: schema.TypeInt, Optional: true, ValidateFunc: validation.IntAtLeast(1)}},
+			"threshold":            {key: "threshold", schema: &schema.Schema{Type: schema.TypeList, Required: true, MinItems: 1, MaxItems: 2, Elem: &schema.Schema{Type: schema.TypeFloat}}},
+			"threshold_comparator": {key: "thresholdComparator", schema: &schema.Schema{Type: schema.TypeString, Required: true, ValidateFunc: validation.StringInSlice(ruleThresholdComparators, false)}},
+			"time_field":           {key: "timeField", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+			"time_window_size":     {key: "timeWindowSize", schema: &schema.Schema{Type: schema.TypeInt, Required: true, ValidateFunc: validation.IntAtLeast(1)}},
+			"time_window_unit":     {key: "timeWindowUnit", schema: &schema.Schema{Type: schema.TypeString, Required: true, ValidateFunc: validation.StringInSlice(ruleTimeUnits, false)}},
+		},
+	},
+	"logs_document_count": {
+		ruleTypeID: "logs.alert.document.count",
+		fields: map[string]ruleParamsField{
+			"count": {
+				key:    "count",
+				schema: &schema.Schema{Type: schema.TypeList, Required: true, MaxItems: 1},
+				fields: map[string]ruleParamsField{
+					"comparator": {key: "comparator", schema: &schema.Schema{Type: schema.TypeString, Required: true, ValidateFunc: validation.StringInSlice([]string{"more than", "more than or equals", "less than", "less than or equals", "equals", "does not equal"}, false)}},
+					"value":      {key: "value", schema: &schema.Schema{Type: schema.TypeInt, Required: true, ValidateFunc: validation.IntAtLeast(0)}},
+				},
+			},
+			"criteria": {
+				key:    "criteria",
+				schema: &schema.Schema{Type: schema.TypeList, Required: true, MinItems: 1},
+				fields: map[string]ruleParamsField{
+					"comparator": {key: "comparator", schema: &schema.Schema{Type: schema.TypeString, Required: true, ValidateFunc: validation.StringInSlice([]string{"more than", "more than or equals", "less than", "less than or equals", "equals", "does not equal", "matches", "does not match", "matches phrase", "does not match phrase"}, false)}},
+					"field":      {key: "field", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+					"value":      {key: "value", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+				},
+			},
+			"group_by":  {key: "groupBy", schema: &schema.Schema{Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}}},
+			"time_size": {key: "timeSize", schema: &schema.Schema{Type: schema.TypeInt, Required: true, ValidateFunc: validation.IntAtLeast(1)}},
+			"time_unit": {key: "timeUnit", schema: &schema.Schema{Type: schema.TypeString, Required: true, ValidateFunc: validation.StringInSlice(ruleTimeUnits, false)}},
+		},
+	},
+	"metrics_threshold": {
+		ruleTypeID: "metrics.alert.threshold",
+		fields: map[string]ruleParamsField{
+			"alert_on_no_data": {key: "alertOnNoData", schema: &schema.Schema{Type: schema.TypeBool, Optional: true}},
+			"criteria": {
+				key:    "criteria",
+				schema: &schema.Schema{Type: schema.TypeList, Required: true, MinItems: 1},
+				fields: map[string]ruleParamsField{
+					"agg_type":           {key: "aggType", schema: &schema.Schema{Type: schema.TypeString, Required: true, ValidateFunc: validation.StringInSlice([]string{"avg", "max", "min", "cardinality", "rate", "count", "sum", "p95", "p99"}, false)}},
+					"comparator":         {key: "comparator", schema: &schema.Schema{Type: schema.TypeString, Required: true, ValidateFunc: validation.StringInSlice(ruleThresholdComparators, false)}},
+					"metric":             {key: "metric", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+					"threshold":          {key: "threshold", schema: &schema.Schema{Type: schema.TypeList, Required: true, MinItems: 1, MaxItems: 2, Elem: &schema.Schema{Type: schema.TypeFloat}}},
+					"time_size":          {key: "timeSize", schema: &schema.Schema{Type: schema.TypeInt, Required: true, ValidateFunc: validation.IntAtLeast(1)}},
+					"time_unit":          {key: "timeUnit", schema: &schema.Schema{Type: schema.TypeString, Required: true, ValidateFunc: validation.StringInSlice(ruleTimeUnits, false)}},
+					"warning_comparator": {key: "warningComparator", schema: &schema.Schema{Type: schema.TypeString, Optional: true, ValidateFunc: validation.StringInSlice(ruleThresholdComparators, false)}},
+					"warning_threshold":  {key: "warningThreshold", schema: &schema.Schema{Type: schema.TypeList, Optional: true, MaxItems: 2, Elem: &schema.Schema{Type: schema.TypeFloat}}},
+				},
+			},
+			"filter_query": {key: "filterQuery", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+			"group_by":     {key: "groupBy", schema: &schema.Schema{Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}}},
+			"source_id":    {key: "sourceId", schema: &schema.Schema{Type: schema.TypeString, Optional: true, Default: "default"}},
+		},
+	},
+	"uptime_monitor_status": {
+		ruleTypeID: "xpack.uptime.alerts.monitorStatus",
+		fields: map[string]ruleParamsField{
+			"availability": {
+				key:    "availability",
+				schema: &schema.Schema{Type: schema.TypeList, Optional: true, MaxItems: 1},
+				fields: map[string]ruleParamsField{
+					"range":      {key: "range", schema: &schema.Schema{Type: schema.TypeInt, Required: true, ValidateFunc: validation.IntAtLeast(1)}},
+					"range_unit": {key: "rangeUnit", schema: &schema.Schema{Type: schema.TypeString, Required: true, ValidateFunc: validation.StringInSlice([]string{"s", "m", "h", "d", "w", "M", "y"}, false)}},
+					"threshold":  {key: "threshold", schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+				},
+			},
+			"num_times":                 {key: "numTimes", schema: &schema.Schema{Type: schema.TypeInt, Required: true, ValidateFunc: validation.IntAtLeast(1)}},
+			"search":                    {key: "search", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+			"should_check_availability": {key: "shouldCheckAvailability", schema: &schema.Schema{Type: schema.TypeBool, Optional: true}},
+			"should_check_status":       {key: "shouldCheckStatus", schema: &schema.Schema{Type: schema.TypeBool, Optional: true, Default: true}},
+			"timerange_count":           {key: "timerangeCount", schema: &schema.Schema{Type: schema.TypeInt, Required: true, ValidateFunc: validation.IntAtLeast(1)}},
+			"timerange_unit":            {key: "timerangeUnit", schema: &schema.Schema{Type: schema.TypeString, Required: true, ValidateFunc: validation.StringInSlice(ruleTimeUnits, false)}},
+		},
+	},
+}
+
+// legacyRuleParamsFields - flat param_* attributes, which map the params of
+// index threshold and Elasticsearch query rules
+var legacyRuleParamsFields = map[string]ruleParamsField{
+	"param_agg_field":            {key: "aggField", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+	"param_agg_type":             {key: "aggType", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+	"param_es_query":             {key: "esQuery", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+	"param_group_by":             {key: "groupBy", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+	"param_index":                {key: "index", schema: &schema.Schema{Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}}},
+	"param_size":                 {key: "size", schema: &schema.Schema{Type: schema.TypeInt, Optional: true}},
+	"param_term_field":           {key: "termField", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+	"param_term_size":            {key: "termSize", schema: &schema.Schema{Type: schema.TypeInt, Optional: true}},
+	"param_threshold":            {key: "threshold", schema: &schema.Schema{Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeInt}}},
+	"param_threshold_comparator": {key: "thresholdComparator", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+	"param_time_field":           {key: "timeField", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+	"param_time_window_size":     {key: "timeWindowSize", schema: &schema.Schema{Type: schema.TypeInt, Optional: true}},
+	"param_time_window_unit":     {key: "timeWindowUnit", schema: &schema.Schema{Type: schema.TypeString, Optional: true}},
+}
+
+// ruleParamsBlockNames - sorted names of the typed rule params blocks
+func ruleParamsBlockNames() []string {
+	var names []string
+	for name := range ruleParamsTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// legacyRuleParamsNames - sorted names of the param_* attributes
+func legacyRuleParamsNames() []string {
+	var names []string
+	for name := range legacyRuleParamsFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ruleParamsSchemas - schema of the generic params, the typed rule params
+// blocks and the param_* attributes, of which only one kind can be used by a
+// rule
+func ruleParamsSchemas() map[string]*schema.Schema {
+	blockNames := ruleParamsBlockNames()
+	legacyNames := legacyRuleParamsNames()
+
+	schemas := ruleParamsFieldSchemas(legacyRuleParamsFields)
+	for _, name := range legacyNames {
+		schemas[name].ConflictsWith = append(append([]string{}, blockNames...), "params")
+	}
+
+	schemas["params"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		ConflictsWith:    append(append([]string{}, blockNames...), legacyNames...),
+		ValidateFunc:     validation.StringIsJSON,
+		DiffSuppressFunc: utils.SuppressEquivalentJSONDiffs,
+	}
+
+	for _, name := range blockNames {
+		var conflicts []string
+		for _, other := range blockNames {
+			if other != name {
+				conflicts = append(conflicts, other)
+			}
+		}
+
+		schemas[name] = &schema.Schema{
+			Type:          schema.TypeList,
+			Optional:      true,
+			MaxItems:      1,
+			ConflictsWith: append(append(conflicts, legacyNames...), "params"),
+			Elem: &schema.Resource{
+				Schema: ruleParamsFieldSchemas(ruleParamsTypes[name].fields),
+			},
+		}
+	}
+	return schemas
+}
+
+// ruleParamsFieldSchemas - schema of the fields of a typed rule params block
+func ruleParamsFieldSchemas(fields map[string]ruleParamsField) map[string]*schema.Schema {
+	schemas := map[string]*schema.Schema{}
+	for name, field := range fields {
+		fieldSchema := *field.schema
+		if field.json {
+			fieldSchema.ValidateFunc = validation.StringIsJSON
+			fieldSchema.DiffSuppressFunc = utils.SuppressEquivalentJSONDiffs
+		}
+		if field.fields != nil {
+			fieldSchema.Elem = &schema.Resource{
+				Schema: ruleParamsFieldSchemas(field.fields),
+			}
+		}
+		schemas[name] = &fieldSchema
+	}
+	return schemas
+}
+
+// ruleParamsBlock - returns the typed rule params block in use, if any
+func ruleParamsBlock(d interface{ Get(string) interface{} }) (string, ruleParamsType, bool) {
+	for _, name := range ruleParamsBlockNames() {
+		if len(d.Get(name).([]interface{})) > 0 {
+			return name, ruleParamsTypes[name], true
+		}
+	}
+	return "", ruleParamsType{}, false
+}
+
+// ruleParamsBlockForType - returns the name of the typed rule params block of
+// a rule type, if any
+func ruleParamsBlockForType(ruleTypeID string) (string, bool) {
+	for name, t := range ruleParamsTypes {
+		if t.ruleTypeID == ruleTypeID {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// legacyRuleParamsInUse - checks if the rule params are managed with the
+// param_* attributes
+func legacyRuleParamsInUse(d *schema.ResourceData) bool {
+	for name := range legacyRuleParamsFields {
+		if _, ok := d.GetOk(name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// expandRuleParams - maps the generic params, the typed rule params block or
+// the param_* attributes to the rule params
+func expandRuleParams(d *schema.ResourceData) (map[string]interface{}, error) {
+	if v, ok := d.GetOk("params"); ok {
+		var params map[string]interface{}
+		err := json.Unmarshal([]byte(v.(string)), &params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON in params: %w", err)
+		}
+		return params, nil
+	}
+
+	if name, t, ok := ruleParamsBlock(d); ok {
+		block, _ := d.Get(name).([]interface{})[0].(map[string]interface{})
+		return expandRuleParamsFields(t.fields, block)
+	}
+
+	values := map[string]interface{}{}
+	for name := range legacyRuleParamsFields {
+		values[name] = d.Get(name)
+	}
+	return expandRuleParamsFields(legacyRuleParamsFields, values)
+}
+
+// flattenRuleParams - maps the rule params to the generic params when in use,
+// otherwise to the typed rule params block of the rule type or the param_*
+// attributes, falling back to the generic params for rule types without
+// typed block
+func flattenRuleParams(d *schema.ResourceData, ruleTypeID string, params map[string]interface{}) error {
+	_, paramsInUse := d.GetOk("params")
+	legacyInUse := legacyRuleParamsInUse(d)
+
+	if name, ok := ruleParamsBlockForType(ruleTypeID); ok && !paramsInUse && !legacyInUse {
+		block, err := flattenRuleParamsFields(ruleParamsTypes[name].fields, params)
+		if err != nil {
+			return err
+		}
+		return d.Set(name, []interface{}{block})
+	}
+
+	if paramsInUse || !legacyInUse {
+		// keeps only the configured params, as Kibana adds the defaults of
+		// the rule type
+		var configured map[string]interface{}
+		if paramsInUse && json.Unmarshal([]byte(d.Get("params").(string)), &configured) == nil {
+			params = configuredRuleParams(params, configured)
+		}
+
+		paramsValue, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		return d.Set("params", string(paramsValue))
+	}
+
+	values, err := flattenRuleParamsFields(legacyRuleParamsFields, params)
+	if err != nil {
+		return err
+	}
+	for name, value := range values {
+		err = d.Set(name, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configuredRuleParams - returns the rule params, and the keys of nested
+// objects, that are in the configured params
+func configuredRuleParams(params, configured map[string]interface{}) map[string]interface{} {
+	filtered := map[string]interface{}{}
+	for key, value := range params {
+		configuredValue, ok := configured[key]
+		if !ok {
+			continue
+		}
+
+		object, isObject := value.(map[string]interface{})
+		configuredObject, isConfiguredObject := configuredValue.(map[string]interface{})
+		if isObject && isConfiguredObject {
+			value = configuredRuleParams(object, configuredObject)
+		}
+		filtered[key] = value
+	}
+	return filtered
+}
+
+// expandRuleParamsFields - maps the values of rule params fields to the rule
+// params, leaving unset optional values for Kibana to default
+func expandRuleParamsFields(fields map[string]ruleParamsField, values map[string]interface{}) (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+	for name, field := range fields {
+		value, ok := values[name]
+		if !ok || value == nil {
+			continue
+		}
+		if field.schema.Optional && isZeroRuleParam(value) {
+			continue
+		}
+
+		switch {
+		case field.json:
+			var object interface{}
+			err := json.Unmarshal([]byte(value.(string)), &object)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSON in %s: %w", name, err)
+			}
+			params[field.key] = object
+		case field.fields != nil:
+			var objects []interface{}
+			for _, v := range value.([]interface{}) {
+				nested, _ := v.(map[string]interface{})
+				object, err := expandRuleParamsFields(field.fields, nested)
+				if err != nil {
+					return nil, err
+				}
+				objects = append(objects, object)
+			}
+			if field.schema.MaxItems == 1 {
+				if len(objects) > 0 {
+					params[field.key] = objects[0]
+				}
+			} else {
+				params[field.key] = objects
+			}
+		default:
+			params[field.key] = value
+		}
+	}
+	return params, nil
+}
+
+// flattenRuleParamsFields - maps the rule params to the values of rule params
+// fields
+func flattenRuleParamsFields(fields map[string]ruleParamsField, params map[string]interface{}) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	for name, field := range fields {
+		value, ok := params[field.key]
+		if !ok || value == nil {
+			values[name] = nil
+			continue
+		}
+
+		switch {
+		case field.json:
+			object, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			values[name] = string(object)
+		case field.fields != nil:
+			objects, ok := value.([]interface{})
+			if !ok {
+				objects = []interface{}{value}
+			}
+
+			var nested []interface{}
+			for _, v := range objects {
+				object, ok := v.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("unexpected value %v for %s", v, name)
+				}
+				flattened, err := flattenRuleParamsFields(field.fields, object)
+				if err != nil {
+					return nil, err
+				}
+				nested = append(nested, flattened)
+			}
+			values[name] = nested
+		case field.schema.Type == schema.TypeList:
+			elemSchema, _ := field.schema.Elem.(*schema.Schema)
+			list, ok := value.([]interface{})
+			if !ok {
+				list = []interface{}{value}
+			}
+
+			var flattened []interface{}
+			for _, v := range list {
+				elem, err := flattenRuleParamValue(elemSchema.Type, v)
+				if err != nil {
+					return nil, fmt.Errorf("unexpected value %v for %s", v, name)
+				}
+				flattened = append(flattened, elem)
+			}
+			values[name] = flattened
+		default:
+			flattened, err := flattenRuleParamValue(field.schema.Type, value)
+			if err != nil {
+				return nil, fmt.Errorf("unexpected value %v for %s", value, name)
+			}
+			values[name] = flattened
+		}
+	}
+	return values, nil
+}
+
+// flattenRuleParamValue - converts a JSON value to the value of an attribute type
+func flattenRuleParamValue(valueType schema.ValueType, value interface{}) (interface{}, error) {
+	switch valueType {
+	case schema.TypeInt:
+		number, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("not a number")
+		}
+		return int(number), nil
+	case schema.TypeFloat:
+		number, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("not a number")
+		}
+		return number, nil
+	case schema.TypeString:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		return fmt.Sprint(value), nil
+	default:
+		return value, nil
+	}
+}
+
+// isZeroRuleParam - checks if a value is the zero value of its attribute type
+func isZeroRuleParam(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v == ""
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
@@ -1,13 +1,22 @@
 package kibana
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
-	gk "github.com/renato0307/go-kibana/kibana"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestAccKibanaAlertingRule_basic(t *testing.T) {
@@ -22,12 +31,931 @@ func TestAccKibanaAlertingRule_basic(t *testing.T) {
 				Config: testAccKibanaAlertingRule(resourceName),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckKibanaAlertingRuleExists(resourceName),
+					resource.TestCheckResourceAttr("kibana_alerting_rule."+resourceName, "space_id", os.Getenv("KIBANA_SPACE")),
+				),
+			},
+			{
+				ResourceName:      "kibana_alerting_rule." + resourceName,
+				ImportState:       true,
+				ImportStateIdFunc: testAccSpaceObjectImportID("kibana_alerting_rule." + resourceName),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestResourceAlertingRuleRead_notFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceAlertingRule().Schema, map[string]interface{}{})
+	d.SetId("0a037d60")
+
+	diags := resourceAlertingRuleRead(context.Background(), d, testClient(t, ts))
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, diag.Warning, diags[0].Severity)
+	}
+	assert.Equal(t, "", d.Id())
+
+	d.SetId("0a037d60")
+	diags = resourceAlertingRuleDelete(context.Background(), d, testClient(t, ts))
+	assert.False(t, diags.HasError())
+}
+
+func TestResourceAlertingRuleActionHash(t *testing.T) {
+	a := map[string]interface{}{"id": "c55b6eb0", "group": "threshold met", "params": `{"level": "info", "message": null}`}
+	b := map[string]interface{}{"id": "c55b6eb0", "group": "threshold met", "params": "{\"level\":\"info\"}"}
+	c := map[string]interface{}{"id": "c55b6eb0", "group": "threshold met", "params": `{"level": "warning"}`}
+
+	assert.Equal(t, resourceAlertingRuleActionHash(a), resourceAlertingRuleActionHash(b))
+	assert.NotEqual(t, resourceAlertingRuleActionHash(a), resourceAlertingRuleActionHash(c))
+}
+
+func TestAccKibanaAlertingRule_typed(t *testing.T) {
+	resourceName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckKibanaAlertingRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKibanaAlertingRuleTyped(resourceName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKibanaAlertingRuleExists(resourceName),
+					resource.TestCheckResourceAttr("kibana_alerting_rule."+resourceName, "rule_type_id", ".index-threshold"),
+					resource.TestCheckResourceAttr("kibana_alerting_rule."+resourceName, "index_threshold.0.agg_type", "count"),
+					resource.TestCheckResourceAttr("kibana_alerting_rule."+resourceName, "index_threshold.0.threshold.0", "10"),
+				),
+			},
+			{
+				ResourceName:      "kibana_alerting_rule." + resourceName,
+				ImportState:       true,
+				ImportStateIdFunc: testAccSpaceObjectImportID("kibana_alerting_rule." + resourceName),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestExpandFlattenRuleParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceAlertingRule().Schema, map[string]interface{}{
+		"metrics_threshold": []interface{}{
+			map[string]interface{}{
+				"group_by": []interface{}{"host.name"},
+				"criteria": []interface{}{
+					map[string]interface{}{
+						"agg_type":   "avg",
+						"comparator": ">",
+						"metric":     "system.cpu.user.pct",
+						"threshold":  []interface{}{0.9},
+						"time_size":  5,
+						"time_unit":  "m",
+					},
+				},
+			},
+		},
+	})
+
+	params, err := expandRuleParams(d)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, map[string]interface{}{
+		"alertOnNoData": false,
+		"groupBy":       []interface{}{"host.name"},
+		"sourceId":      "default",
+		"criteria": []interface{}{
+			map[string]interface{}{
+				"aggType":    "avg",
+				"comparator": ">",
+				"metric":     "system.cpu.user.pct",
+				"threshold":  []interface{}{0.9},
+				"timeSize":   5,
+				"timeUnit":   "m",
+			},
+		},
+	}, params)
+
+	err = flattenRuleParams(d, "metrics.alert.threshold", map[string]interface{}{
+		"sourceId": "default",
+		"criteria": []interface{}{
+			map[string]interface{}{
+				"aggType":          "max",
+				"comparator":       "between",
+				"threshold":        []interface{}{float64(1), 2.5},
+				"timeSize":         float64(10),
+				"timeUnit":         "m",
+				"warningThreshold": nil,
+			},
+		},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "max", d.Get("metrics_threshold.0.criteria.0.agg_type"))
+		assert.Equal(t, []interface{}{float64(1), 2.5}, d.Get("metrics_threshold.0.criteria.0.threshold"))
+		assert.Equal(t, 10, d.Get("metrics_threshold.0.criteria.0.time_size"))
+		assert.Equal(t, []interface{}{}, d.Get("metrics_threshold.0.group_by"))
+	}
+}
+
+func TestExpandFlattenRuleParams_legacy(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceAlertingRule().Schema, map[string]interface{}{
+		"rule_type_id":               ".index-threshold",
+		"param_index":                []interface{}{"my-index*"},
+		"param_threshold":            []interface{}{1},
+		"param_threshold_comparator": ">",
+		"param_time_window_size":     5,
+	})
+
+	params, err := expandRuleParams(d)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, map[string]interface{}{
+		"index":               []interface{}{"my-index*"},
+		"threshold":           []interface{}{1},
+		"thresholdComparator": ">",
+		"timeWindowSize":      5,
+	}, params)
+
+	// keeps using the param_* attributes when they are in use
+	err = flattenRuleParams(d, ".index-threshold", map[string]interface{}{
+		"index":          []interface{}{"other-index*"},
+		"timeWindowSize": float64(10),
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []interface{}{"other-index*"}, d.Get("param_index"))
+		assert.Equal(t, 10, d.Get("param_time_window_size"))
+		assert.Equal(t, 0, d.Get("index_threshold.#"))
+	}
+}
+
+func TestAccKibanaAlertingRule_params(t *testing.T) {
+	resourceName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckKibanaAlertingRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKibanaAlertingRuleParams(resourceName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKibanaAlertingRuleExists(resourceName),
+					resource.TestCheckResourceAttr("kibana_alerting_rule."+resourceName, "index_threshold.#", "0"),
 				),
 			},
 		},
 	})
 }
 
+func TestAccKibanaAlertingRule_enabled(t *testing.T) {
+	resourceName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckKibanaAlertingRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKibanaAlertingRuleEnabled(resourceName, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKibanaAlertingRuleExists(resourceName),
+					resource.TestCheckResourceAttr("kibana_alerting_rule."+resourceName, "enabled", "false"),
+				),
+			},
+			{
+				Config: testAccKibanaAlertingRuleEnabled(resourceName, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKibanaAlertingRuleExists(resourceName),
+					resource.TestCheckResourceAttr("kibana_alerting_rule."+resourceName, "enabled", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKibanaAlertingRule_snooze(t *testing.T) {
+	resourceName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckKibanaAlertingRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKibanaAlertingRuleSnooze(resourceName, "2h"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKibanaAlertingRuleExists(resourceName),
+					resource.TestCheckResourceAttr("kibana_alerting_rule."+resourceName, "snooze_schedule.#", "1"),
+				),
+			},
+			{
+				Config: testAccKibanaAlertingRuleSnooze(resourceName, "3h"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKibanaAlertingRuleExists(resourceName),
+					resource.TestCheckResourceAttr("kibana_alerting_rule."+resourceName, "snooze_schedule.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestUpdateRuleEnabled(t *testing.T) {
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		calls = append(calls, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c := testClient(t, ts)
+	d := schema.TestResourceDataRaw(t, resourceAlertingRule().Schema, map[string]interface{}{
+		"enabled": false,
+	})
+	d.SetId("0a037d60")
+
+	assert.NoError(t, updateRuleEnabled(d, c, false))
+	assert.Empty(t, calls)
+
+	assert.NoError(t, updateRuleEnabled(d, c, true))
+	assert.Equal(t, []string{"/s/testSpace/api/alerting/rule/0a037d60/_disable"}, calls)
+
+	d = schema.TestResourceDataRaw(t, resourceAlertingRule().Schema, map[string]interface{}{})
+	d.SetId("0a037d60")
+	calls = nil
+
+	assert.NoError(t, updateRuleEnabled(d, c, false))
+	assert.Equal(t, []string{"/s/testSpace/api/alerting/rule/0a037d60/_enable"}, calls)
+}
+
+func TestResourceAlertingRuleCreate_disabled(t *testing.T) {
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.Method == "POST" {
+			payload := map[string]interface{}{}
+			_ = json.Unmarshal(body, &payload)
+			assert.Equal(t, false, payload["enabled"])
+		}
+		fmt.Fprint(w, `{"id": "0a037d60", "name": "my-rule", "enabled": false, "rule_type_id": ".index-threshold", "params": {}, "actions": []}`)
+	}))
+	defer ts.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceAlertingRule().Schema, map[string]interface{}{
+		"name":              "my-rule",
+		"consumer":          "alerts",
+		"rule_type_id":      ".index-threshold",
+		"schedule_interval": "1m",
+		"params":            "{}",
+		"enabled":           false,
+	})
+
+	diags := resourceAlertingRuleCreate(context.Background(), d, testClient(t, ts))
+	assert.Empty(t, diags)
+	assert.Equal(t, []string{
+		"POST /s/testSpace/api/alerting/rule",
+		"GET /s/testSpace/api/alerting/rule/0a037d60",
+	}, calls)
+	assert.Equal(t, false, d.Get("enabled"))
+}
+
+func TestExpandFlattenRuleParams_generic(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceAlertingRule().Schema, map[string]interface{}{
+		"rule_type_id": "siem.queryRule",
+		"params":       `{"query": "host.name: *", "riskScore": 21, "severity": "low", "filters": null}`,
+	})
+
+	params, err := expandRuleParams(d)
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]interface{}{
+			"query":     "host.name: *",
+			"riskScore": float64(21),
+			"severity":  "low",
+			"filters":   nil,
+		}, params)
+	}
+
+	// keeps the generic params for rule types with typed block
+	err = flattenRuleParams(d, ".index-threshold", map[string]interface{}{"aggType": "count", "severity": "high"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"severity":"high"}`, d.Get("params"))
+		assert.Equal(t, 0, d.Get("index_threshold.#"))
+	}
+
+	// uses the generic params for imported rules without typed block
+	d = schema.TestResourceDataRaw(t, resourceAlertingRule().Schema, map[string]interface{}{})
+	err = flattenRuleParams(d, "xpack.ml.anomaly_detection_alert", map[string]interface{}{"jobSelection": map[string]interface{}{"jobIds": []interface{}{"my-job"}}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"jobSelection":{"jobIds":["my-job"]}}`, d.Get("params"))
+		assert.Equal(t, 0, d.Get("param_index.#"))
+	}
+}
+
+func TestFlattenRuleParams_genericDefaults(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceAlertingRule().Schema, map[string]interface{}{
+		"rule_type_id": "siem.queryRule",
+		"params":       `{"query": "host.name: *", "threat": {"framework": "MITRE ATT&CK"}}`,
+	})
+
+	// Kibana returns the params with the defaults of the rule type
+	err := flattenRuleParams(d, "siem.queryRule", map[string]interface{}{
+		"query":      "host.name: *",
+		"language":   "kuery",
+		"maxSignals": float64(100),
+		"threat":     map[string]interface{}{"framework": "MITRE ATT&CK", "tactic": map[string]interface{}{}},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"query":"host.name: *","threat":{"framework":"MITRE ATT\u0026CK"}}`, d.Get("params"))
+	}
+
+	// changes of configured params are still read
+	err = flattenRuleParams(d, "siem.queryRule", map[string]interface{}{
+		"query":    "host.name: web-*",
+		"language": "kuery",
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"query":"host.name: web-*"}`, d.Get("params"))
+	}
+}
+
+func TestExpandCreateRule_throttle(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceAlertingRule().Schema, map[string]interface{}{
+		"name":              "test rule",
+		"consumer":          "alerts",
+		"rule_type_id":      "siem.queryRule",
+		"params":            `{"query": "host.name: *"}`,
+		"schedule_interval": "1m",
+		"notify_when":       "onThrottleInterval",
+		"throttle":          "10m",
+	})
+
+	rule, err := expandCreateRule(d)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "onThrottleInterval", rule.NotifyWhen)
+		assert.Equal(t, "10m", rule.Throttle)
+	}
+}
+
+func TestResourceAlertingRuleCustomizeDiff(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "0a037d60",
+		Attributes: map[string]string{
+			"id":                           "0a037d60",
+			"name":                         "my-rule",
+			"consumer":                     "alerts",
+			"notify_when":                  "onActiveAlert",
+			"schedule_interval":            "1m",
+			"rule_type_id":                 "apm.error_rate",
+			"apm_error_rate.#":             "1",
+			"apm_error_rate.0.threshold":   "25",
+			"apm_error_rate.0.window_size": "5",
+			"apm_error_rate.0.window_unit": "m",
+			"apm_error_rate.0.environment": "ENVIRONMENT_ALL",
+		},
+	}
+
+	config := map[string]interface{}{
+		"name":              "my-rule",
+		"consumer":          "alerts",
+		"notify_when":       "onActiveAlert",
+		"schedule_interval": "1m",
+		"action":            []interface{}{},
+		"index_threshold": []interface{}{
+			map[string]interface{}{
+				"index":                []interface{}{"my-index*"},
+				"threshold":            []interface{}{10},
+				"threshold_comparator": ">",
+				"time_field":           "@timestamp",
+				"time_window_size":     5,
+				"time_window_unit":     "m",
+			},
+		},
+	}
+
+	d, err := resourceAlertingRule().Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), nil)
+	if assert.NoError(t, err) && assert.NotNil(t, d) {
+		assert.Equal(t, ".index-threshold", d.Attributes["rule_type_id"].New)
+		assert.True(t, d.RequiresNew())
+	}
+
+	delete(config, "index_threshold")
+	_, err = expandCreateRule(schema.TestResourceDataRaw(t, resourceAlertingRule().Schema, config))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "rule_type_id is required")
+	}
+}
+
+func TestResourceAlertingRuleDiff_legacyServer(t *testing.T) {
+	config := map[string]interface{}{
+		"name":              "my-rule",
+		"consumer":          "alerts",
+		"rule_type_id":      ".index-threshold",
+		"schedule_interval": "1m",
+		"params":            "{}",
+		"action":            []interface{}{},
+	}
+
+	c := &apiClient{version: "7.10.2"}
+	d, err := resourceAlertingRule().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), c)
+	if assert.NoError(t, err) && assert.NotNil(t, d) {
+		assert.Equal(t, "my-rule", d.Attributes["name"].New)
+	}
+
+	config["notify_when"] = "onActiveAlert"
+	_, err = resourceAlertingRule().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), c)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "notify_when requires Kibana 7.11.0 or later")
+	}
+}
+
+func TestResourceAlertingRuleActionsCustomizeDiff(t *testing.T) {
+	config := map[string]interface{}{
+		"name":              "my-rule",
+		"consumer":          "alerts",
+		"rule_type_id":      ".index-threshold",
+		"schedule_interval": "1m",
+		"action": []interface{}{
+			map[string]interface{}{
+				"id":     "c55b6eb0",
+				"group":  "threshold met",
+				"params": `{"message": "{{context.message}}"}`,
+				"frequency": []interface{}{
+					map[string]interface{}{
+						"summary":     true,
+						"notify_when": "onThrottleInterval",
+						"throttle":    "1h",
+					},
+				},
+			},
+		},
+	}
+
+	diff := func(version string) error {
+		c := &apiClient{version: version}
+		_, err := resourceAlertingRule().Diff(context.Background(), &terraform.InstanceState{}, terraform.NewResourceConfigRaw(config), c)
+		return err
+	}
+
+	assert.NoError(t, diff("8.6.0"))
+
+	err := diff("8.5.3")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "action.frequency requires Kibana 8.6.0 or later, but the server is running 8.5.3")
+	}
+
+	config["notify_when"] = "onActiveAlert"
+	err = diff("8.6.0")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "notify_when and throttle cannot be set when an action uses frequency")
+	}
+}
+
+func TestExpandFlattenRuleActions(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceAlertingRule().Schema, map[string]interface{}{
+		"action": []interface{}{
+			map[string]interface{}{
+				"id":     "c55b6eb0",
+				"group":  "threshold met",
+				"params": `{"message": "summary"}`,
+				"frequency": []interface{}{
+					map[string]interface{}{"summary": true, "notify_when": "onThrottleInterval", "throttle": "1h"},
+				},
+			},
+			map[string]interface{}{
+				"id":     "a1b2c3d4",
+				"group":  "threshold met",
+				"params": `{"summary": "page"}`,
+				"frequency": []interface{}{
+					map[string]interface{}{"summary": false, "notify_when": "onActionGroupChange"},
+				},
+			},
+		},
+	})
+
+	actions, err := expandRuleActions(d)
+	if !assert.NoError(t, err) || !assert.Len(t, actions, 2) {
+		return
+	}
+
+	// an unset throttle is sent as null
+	for _, action := range actions {
+		if action.ID == "a1b2c3d4" {
+			payload, _ := json.Marshal(action.Frequency)
+			assert.JSONEq(t, `{"summary": false, "notify_when": "onActionGroupChange", "throttle": null}`, string(payload))
+		}
+	}
+
+	r := &rule{Actions: actions}
+	flattened, err := flattenRuleActions(r)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, a := range flattened {
+		a := a.(map[string]interface{})
+		frequency := a["frequency"].([]interface{})[0].(map[string]interface{})
+		switch a["id"] {
+		case "c55b6eb0":
+			assert.Equal(t, true, frequency["summary"])
+			assert.Equal(t, "1h", frequency["throttle"])
+		case "a1b2c3d4":
+			assert.Equal(t, "onActionGroupChange", frequency["notify_when"])
+			assert.Equal(t, "", frequency["throttle"])
+		}
+	}
+
+	// the hash of an action changes with its frequency
+	a := flattened[0].(map[string]interface{})
+	b := map[string]interface{}{"id": a["id"], "group": a["group"], "params": a["params"]}
+	assert.NotEqual(t, resourceAlertingRuleActionHash(a), resourceAlertingRuleActionHash(b))
+}
+
+func TestUpdateRuleMutes(t *testing.T) {
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		calls = append(calls, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	r := resourceAlertingRule()
+	prior := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":              "my-rule",
+		"consumer":          "alerts",
+		"rule_type_id":      ".index-threshold",
+		"schedule_interval": "1m",
+		"params":            "{}",
+		"mute_all":          true,
+		"muted_alert_ids":   []interface{}{"host-1", "host-2"},
+	})
+	prior.SetId("0a037d60")
+	state := prior.State()
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":              "my-rule",
+		"consumer":          "alerts",
+		"rule_type_id":      ".index-threshold",
+		"schedule_interval": "1m",
+		"params":            "{}",
+		"action":            []interface{}{},
+		"mute_all":          false,
+		"muted_alert_ids":   []interface{}{"host-2", "host/3"},
+	})
+
+	diff, err := r.Diff(context.Background(), state, config, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	d, err := schema.InternalMap(r.Schema).Data(state, diff)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.False(t, d.HasChangesExcept("mute_all", "muted_alert_ids"))
+
+	err = updateRuleMutes(d, testClient(t, ts))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{
+			"/s/testSpace/api/alerting/rule/0a037d60/_unmute_all",
+			"/s/testSpace/api/alerting/rule/0a037d60/alert/host-1/_unmute",
+			"/s/testSpace/api/alerting/rule/0a037d60/alert/host/3/_mute",
+		}, calls)
+	}
+}
+
+func TestUpdateRuleMutes_removed(t *testing.T) {
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		calls = append(calls, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	r := resourceAlertingRule()
+	prior := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":              "my-rule",
+		"consumer":          "alerts",
+		"rule_type_id":      ".index-threshold",
+		"schedule_interval": "1m",
+		"params":            "{}",
+		"mute_all":          true,
+		"muted_alert_ids":   []interface{}{"host-1"},
+	})
+	prior.SetId("0a037d60")
+	state := prior.State()
+
+	// the mutes are removed from the configuration
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":              "my-rule",
+		"consumer":          "alerts",
+		"rule_type_id":      ".index-threshold",
+		"schedule_interval": "1m",
+		"params":            "{}",
+		"action":            []interface{}{},
+	})
+
+	diff, err := r.Diff(context.Background(), state, config, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	d, err := schema.InternalMap(r.Schema).Data(state, diff)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.False(t, d.HasChangesExcept("mute_all", "muted_alert_ids"))
+
+	err = updateRuleMutes(d, testClient(t, ts))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{
+			"/s/testSpace/api/alerting/rule/0a037d60/_unmute_all",
+			"/s/testSpace/api/alerting/rule/0a037d60/alert/host-1/_unmute",
+		}, calls)
+	}
+}
+
+func TestUpdateRuleSnoozeSchedules(t *testing.T) {
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		calls = append(calls, r.URL.Path+" "+string(body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	r := resourceAlertingRule()
+	prior := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":              "my-rule",
+		"consumer":          "alerts",
+		"rule_type_id":      ".index-threshold",
+		"schedule_interval": "1m",
+		"params":            "{}",
+	})
+	prior.SetId("0a037d60")
+	_ = prior.Set("snooze_schedule", []interface{}{
+		map[string]interface{}{"id": "s1", "duration": "1h0m0s", "rrule": "FREQ=WEEKLY;BYDAY=SA", "start": "2024-05-04T22:00:00.000Z", "timezone": "UTC"},
+		map[string]interface{}{"id": "s2", "duration": "2h0m0s", "rrule": "", "start": "2024-06-01T00:00:00.000Z", "timezone": "UTC"},
+	})
+	state := prior.State()
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":              "my-rule",
+		"consumer":          "alerts",
+		"rule_type_id":      ".index-threshold",
+		"schedule_interval": "1m",
+		"params":            "{}",
+		"action":            []interface{}{},
+		"snooze_schedule": []interface{}{
+			map[string]interface{}{"duration": "60m", "rrule": "FREQ=WEEKLY;BYDAY=SA", "start": "2024-05-04T22:00:00Z"},
+			map[string]interface{}{"duration": "30m", "start": "2024-07-01T00:00:00Z"},
+		},
+	})
+
+	diff, err := r.Diff(context.Background(), state, config, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	d, err := schema.InternalMap(r.Schema).Data(state, diff)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	err = updateRuleSnoozeSchedules(d, testClient(t, ts))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{
+			`/s/testSpace/internal/alerting/rule/0a037d60/_unsnooze {"schedule_ids":["s2"]}`,
+			`/s/testSpace/internal/alerting/rule/0a037d60/_snooze {"snooze_schedule":{"duration":1800000,"rRule":{"dtstart":"2024-07-01T00:00:00Z","tzid":"UTC","count":1}}}`,
+		}, calls)
+	}
+}
+
+func TestResourceAlertingRuleUpdate_apiKeyRotation(t *testing.T) {
+	var calls []string
+	failRotation := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.Method == "POST" {
+			if failRotation {
+				w.WriteHeader(http.StatusInternalServerError)
+			} else {
+				w.WriteHeader(http.StatusNoContent)
+			}
+			return
+		}
+		fmt.Fprint(w, `{"id": "0a037d60", "name": "my-rule", "rule_type_id": ".index-threshold", "api_key_owner": "terraform", "enabled": true, "params": {}}`)
+	}))
+	defer ts.Close()
+
+	r := resourceAlertingRule()
+	newData := func() *schema.ResourceData {
+		prior := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+			"name":                     "my-rule",
+			"consumer":                 "alerts",
+			"rule_type_id":             ".index-threshold",
+			"schedule_interval":        "1m",
+			"params":                   "{}",
+			"api_key_rotation_trigger": "2024-01",
+		})
+		prior.SetId("0a037d60")
+		state := prior.State()
+
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":                     "my-rule",
+			"consumer":                 "alerts",
+			"rule_type_id":             ".index-threshold",
+			"schedule_interval":        "1m",
+			"params":                   "{}",
+			"action":                   []interface{}{},
+			"api_key_rotation_trigger": "2024-02",
+		})
+		diff, err := r.Diff(context.Background(), state, config, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := schema.InternalMap(r.Schema).Data(state, diff)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	d := newData()
+	diags := resourceAlertingRuleUpdate(context.Background(), d, testClient(t, ts))
+	assert.Empty(t, diags)
+	assert.Equal(t, []string{
+		"POST /s/testSpace/api/alerting/rule/0a037d60/_update_api_key",
+		"GET /s/testSpace/api/alerting/rule/0a037d60",
+	}, calls)
+	assert.Equal(t, "terraform", d.Get("api_key_owner"))
+	assert.Equal(t, "2024-02", d.State().Attributes["api_key_rotation_trigger"])
+
+	d = newData()
+	failRotation = true
+	diags = resourceAlertingRuleUpdate(context.Background(), d, testClient(t, ts))
+	assert.Len(t, diags, 1)
+	assert.Equal(t, "2024-01", d.State().Attributes["api_key_rotation_trigger"])
+}
+
+func TestResourceAlertingRuleUpdate_firstRun(t *testing.T) {
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_run_soon"):
+			w.WriteHeader(http.StatusInternalServerError)
+		case r.Method == "POST":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			fmt.Fprint(w, `{"id": "0a037d60", "name": "my-rule", "rule_type_id": ".index-threshold", "enabled": true, "params": {},
+				"execution_status": {"status": "ok", "last_execution_date": "2024-05-04T22:00:00.000Z"}}`)
+		}
+	}))
+	defer ts.Close()
+
+	r := resourceAlertingRule()
+	newData := func(name string, muteAll bool) *schema.ResourceData {
+		prior := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+			"name":                    "my-rule",
+			"consumer":                "alerts",
+			"rule_type_id":            ".index-threshold",
+			"schedule_interval":       "1m",
+			"params":                  "{}",
+			"fail_on_first_run_error": true,
+		})
+		prior.SetId("0a037d60")
+		state := prior.State()
+
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":                    name,
+			"consumer":                "alerts",
+			"rule_type_id":            ".index-threshold",
+			"schedule_interval":       "1m",
+			"params":                  "{}",
+			"action":                  []interface{}{},
+			"fail_on_first_run_error": true,
+			"mute_all":                muteAll,
+		})
+		diff, err := r.Diff(context.Background(), state, config, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := schema.InternalMap(r.Schema).Data(state, diff)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	// the rule definition is unchanged, so its run is not checked
+	diags := resourceAlertingRuleUpdate(context.Background(), newData("my-rule", true), testClient(t, ts))
+	assert.Empty(t, diags)
+	assert.Equal(t, []string{
+		"POST /s/testSpace/api/alerting/rule/0a037d60/_mute_all",
+		"GET /s/testSpace/api/alerting/rule/0a037d60",
+	}, calls)
+
+	// the updated rule cannot be run, so its run is not waited for
+	calls = nil
+	diags = resourceAlertingRuleUpdate(context.Background(), newData("my-renamed-rule", false), testClient(t, ts))
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, diag.Warning, diags[0].Severity)
+		assert.Contains(t, diags[0].Detail, "500")
+	}
+	assert.Equal(t, []string{
+		"PUT /s/testSpace/api/alerting/rule/0a037d60",
+		"POST /s/testSpace/internal/alerting/rule/0a037d60/_run_soon",
+		"GET /s/testSpace/api/alerting/rule/0a037d60",
+	}, calls)
+}
+
+func TestFlattenRule_executionHealth(t *testing.T) {
+	r := rule{}
+	err := json.Unmarshal([]byte(`{
+		"id": "0a037d60",
+		"params": {},
+		"execution_status": {
+			"status": "error",
+			"last_execution_date": "2024-05-04T22:00:00.000Z",
+			"last_duration": 1250,
+			"error": {"reason": "execute", "message": "index my-index not found"}
+		},
+		"monitoring": {
+			"run": {
+				"history": [
+					{"success": true, "timestamp": 1714860000000, "duration": 980, "outcome": "success"},
+					{"success": false, "timestamp": 1714860060000, "duration": 1250, "outcome": "failure"}
+				],
+				"calculated_metrics": {"success_ratio": 0.5}
+			}
+		}
+	}`), &r)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceAlertingRule().Schema, map[string]interface{}{})
+	if !assert.NoError(t, flattenRule(d, &r)) {
+		return
+	}
+	assert.Equal(t, 1250, d.Get("last_execution_duration"))
+	assert.Equal(t, "execute", d.Get("last_execution_error_reason"))
+	assert.Equal(t, "index my-index not found", d.Get("last_execution_error_message"))
+	assert.Equal(t, "", d.Get("last_execution_warning_reason"))
+	assert.Equal(t, 0.5, d.Get("monitoring_success_ratio"))
+	assert.Equal(t, 2, d.Get("monitoring_run_history.#"))
+	assert.Equal(t, "2024-05-04T22:01:00.000Z", d.Get("monitoring_run_history.1.timestamp"))
+	assert.Equal(t, "failure", d.Get("monitoring_run_history.1.outcome"))
+	assert.Equal(t, false, d.Get("monitoring_run_history.1.success"))
+}
+
+func TestFlattenRule_notifyWhen(t *testing.T) {
+	r := rule{ID: "0a037d60", NotifyWhen: "onActiveAlert", Params: map[string]interface{}{}}
+
+	// the default of Kibana is ignored when not configured
+	d := schema.TestResourceDataRaw(t, resourceAlertingRule().Schema, map[string]interface{}{})
+	if assert.NoError(t, flattenRule(d, &r)) {
+		assert.Equal(t, "", d.Get("notify_when"))
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceAlertingRule().Schema, map[string]interface{}{
+		"notify_when": "onThrottleInterval",
+	})
+	if assert.NoError(t, flattenRule(d, &r)) {
+		assert.Equal(t, "onActiveAlert", d.Get("notify_when"))
+	}
+}
+
+func TestWaitForRuleRun(t *testing.T) {
+	responses := []string{
+		`{"id": "0a037d60", "params": {}, "execution_status": {"status": "pending", "last_execution_date": "2024-05-04T22:00:00.000Z"}}`,
+		`{"id": "0a037d60", "params": {}, "execution_status": {"status": "error", "last_execution_date": "2024-05-04T22:00:01.000Z",
+			"error": {"reason": "execute", "message": "index my-index not found"}}}`,
+	}
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/s/testSpace/api/alerting/rule/0a037d60", r.URL.Path)
+		fmt.Fprint(w, responses[calls])
+		if calls < len(responses)-1 {
+			calls++
+		}
+	}))
+	defer ts.Close()
+
+	err := waitForRuleRun(context.Background(), testClient(t, ts), "0a037d60", "2024-05-04T22:00:00.000Z", time.Minute)
+	if assert.Error(t, err) {
+		assert.Equal(t, "the first run of rule 0a037d60 ended in error: execute: index my-index not found", err.Error())
+	}
+
+	responses[1] = `{"id": "0a037d60", "params": {}, "execution_status": {"status": "ok", "last_execution_date": "2024-05-04T22:00:01.000Z"}}`
+	calls = 0
+	err = waitForRuleRun(context.Background(), testClient(t, ts), "0a037d60", "2024-05-04T22:00:00.000Z", time.Minute)
+	assert.NoError(t, err)
+}
+
 func testAccCheckKibanaAlertingRuleExists(resourceName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources["kibana_alerting_rule." + resourceName]
@@ -39,7 +967,7 @@ func testAccCheckKibanaAlertingRuleExists(resourceName string) resource.TestChec
 			return fmt.Errorf("no ID is set")
 		}
 
-		c := testAccProvider.Meta().(*gk.Client)
+		c := testAccProvider.Meta().(*apiClient)
 		_, err := c.GetRule(rs.Primary.ID)
 		if err != nil {
 			return err
@@ -133,8 +1061,148 @@ func testAccKibanaAlertingRule(resourceName string) string {
 			}`, resourceName, resourceName, resourceName)
 }
 
+func testAccKibanaAlertingRuleTyped(resourceName string) string {
+	return fmt.Sprintf(`
+		resource "kibana_alerting_rule" "%s" {
+		  consumer          = "alerts"
+		  name              = "%s"
+		  notify_when       = "onActiveAlert"
+		  schedule_interval = "5m"
+
+		  action {
+		    id     = kibana_actions_connector.%s.id
+		    group  = "threshold met"
+		    params = jsonencode({ "level" : "info", "message" : "{{context.message}}" })
+		  }
+
+		  index_threshold {
+		    index                = ["my-index*"]
+		    threshold            = [10]
+		    threshold_comparator = ">"
+		    time_field           = "@timestamp"
+		    time_window_size     = 5
+		    time_window_unit     = "m"
+		  }
+		}
+
+		resource "kibana_actions_connector" "%s" {
+		  name = "%s"
+
+		  server_log {}
+		}`, resourceName, resourceName, resourceName, resourceName, resourceName)
+}
+
+func testAccKibanaAlertingRuleParams(resourceName string) string {
+	return fmt.Sprintf(`
+		resource "kibana_alerting_rule" "%s" {
+		  consumer          = "alerts"
+		  name              = "%s"
+		  notify_when       = "onActiveAlert"
+		  rule_type_id      = ".index-threshold"
+		  schedule_interval = "5m"
+
+		  action {
+		    id     = kibana_actions_connector.%s.id
+		    group  = "threshold met"
+		    params = jsonencode({ "level" : "info", "message" : "{{context.message}}" })
+		  }
+
+		  params = jsonencode({
+		    "aggType" : "count",
+		    "groupBy" : "all",
+		    "index" : ["my-index*"],
+		    "threshold" : [10],
+		    "thresholdComparator" : ">",
+		    "timeField" : "@timestamp",
+		    "timeWindowSize" : 5,
+		    "timeWindowUnit" : "m"
+		  })
+		}
+
+		resource "kibana_actions_connector" "%s" {
+		  name = "%s"
+
+		  server_log {}
+		}`, resourceName, resourceName, resourceName, resourceName, resourceName)
+}
+
+func testAccKibanaAlertingRuleEnabled(resourceName string, enabled bool) string {
+	return fmt.Sprintf(`
+		resource "kibana_alerting_rule" "%s" {
+		  consumer          = "alerts"
+		  enabled           = %t
+		  name              = "%s"
+		  notify_when       = "onActiveAlert"
+		  rule_type_id      = ".index-threshold"
+		  schedule_interval = "5m"
+
+		  action {
+		    id     = kibana_actions_connector.%s.id
+		    group  = "threshold met"
+		    params = jsonencode({ "level" : "info", "message" : "{{context.message}}" })
+		  }
+
+		  params = jsonencode({
+		    "aggType" : "count",
+		    "groupBy" : "all",
+		    "index" : ["my-index*"],
+		    "threshold" : [10],
+		    "thresholdComparator" : ">",
+		    "timeField" : "@timestamp",
+		    "timeWindowSize" : 5,
+		    "timeWindowUnit" : "m"
+		  })
+		}
+
+		resource "kibana_actions_connector" "%s" {
+		  name = "%s"
+
+		  server_log {}
+		}`, resourceName, enabled, resourceName, resourceName, resourceName, resourceName)
+}
+
+func testAccKibanaAlertingRuleSnooze(resourceName string, duration string) string {
+	return fmt.Sprintf(`
+		resource "kibana_alerting_rule" "%s" {
+		  consumer          = "alerts"
+		  name              = "%s"
+		  notify_when       = "onActiveAlert"
+		  rule_type_id      = ".index-threshold"
+		  schedule_interval = "5m"
+
+		  action {
+		    id     = kibana_actions_connector.%s.id
+		    group  = "threshold met"
+		    params = jsonencode({ "level" : "info", "message" : "{{context.message}}" })
+		  }
+
+		  params = jsonencode({
+		    "aggType" : "count",
+		    "groupBy" : "all",
+		    "index" : ["my-index*"],
+		    "threshold" : [10],
+		    "thresholdComparator" : ">",
+		    "timeField" : "@timestamp",
+		    "timeWindowSize" : 5,
+		    "timeWindowUnit" : "m"
+		  })
+
+		  snooze_schedule {
+		    start    = "2030-01-05T22:00:00Z"
+		    duration = "%s"
+		    rrule    = "FREQ=WEEKLY;BYDAY=SA"
+		  }
+		}
+
+		resource "kibana_actions_connector" "%s" {
+		  name = "%s"
+
+		  server_log {}
+		}`, resourceName, resourceName, resourceName, duration, resourceName, resourceName)
+}
+
 func testAccCheckKibanaAlertingRuleDestroy(s *terraform.State) error {
-	c := testAccProvider.Meta().(*gk.Client)
+	c := testAccProvider.Meta().(*apiClient)
 
 	// loop through the resources in state, verifying each widget
 	// is destroyed
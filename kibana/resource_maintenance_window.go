@@ -0,0 +1,195 @@
+package kibana
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceMaintenanceWindow() *schema.Resource {
+	resourceSchema := map[string]*schema.Schema{
+		"enabled": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		"is_snoozed_until": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"space_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			ForceNew: true,
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"title": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+	}
+
+	// adds the start, duration, rrule and timezone
+	for name, scheduleSchema := range scheduleSchema() {
+		resourceSchema[name] = scheduleSchema
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceMaintenanceWindowCreate,
+		ReadContext:   resourceMaintenanceWindowRead,
+		UpdateContext: resourceMaintenanceWindowUpdate,
+		DeleteContext: resourceMaintenanceWindowDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceImportSpaceObject,
+		},
+		CustomizeDiff: validateAttributeVersions(map[string]serverVersion{
+			"title": versionMaintenanceWindow,
+		}),
+		Schema: resourceSchema,
+	}
+}
+
+// resourceMaintenanceWindowCreate - creates a maintenance window
+func resourceMaintenanceWindowCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+
+	// maps the resource data to a maintenance window
+	w, err := expandMaintenanceWindow(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// calls API to create the maintenance window
+	c := resourceClient(d, m)
+	newWindow, err := c.createMaintenanceWindow(maintenanceWindowCreate{
+		Title:    w.Title,
+		Duration: w.Duration,
+		RRule:    w.RRule,
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(newWindow.ID)
+
+	// disables the maintenance window if requested, as they are created enabled
+	if !w.Enabled {
+		_, err = c.updateMaintenanceWindow(d.Id(), w)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// reads the created maintenance window
+	return resourceMaintenanceWindowRead(ctx, d, m)
+}
+
+// resourceMaintenanceWindowRead - reads a maintenance window
+func resourceMaintenanceWindowRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+
+	// reads the maintenance window from Kibana
+	c := resourceClient(d, m)
+	w, err := c.getMaintenanceWindow(d.Id())
+	if isNotFound(err) {
+		return resourceNotFound(d, "maintenance window")
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// maps the maintenance window to the resource data
+	for name, value := range flattenSchedule(w.Duration, w.RRule) {
+		_ = d.Set(name, value)
+	}
+	_ = d.Set("enabled", w.Enabled)
+	_ = d.Set("is_snoozed_until", maintenanceWindowActiveUntil(w, time.Now()))
+	_ = d.Set("space_id", c.Space)
+	_ = d.Set("status", w.Status)
+	_ = d.Set("title", w.Title)
+
+	return nil
+}
+
+// resourceMaintenanceWindowUpdate - updates a maintenance window
+func resourceMaintenanceWindowUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+
+	// maps the resource data to a maintenance window
+	w, err := expandMaintenanceWindow(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// calls API to update the maintenance window
+	c := resourceClient(d, m)
+	_, err = c.updateMaintenanceWindow(d.Id(), w)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// reads the updated maintenance window and returns
+	return resourceMaintenanceWindowRead(ctx, d, m)
+}
+
+// resourceMaintenanceWindowDelete - deletes a maintenance window
+func resourceMaintenanceWindowDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+
+	c := resourceClient(d, m)
+	err := c.deleteMaintenanceWindow(d.Id())
+	if err != nil && !isNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	d.SetId("")
+
+	return nil
+}
+
+// expandMaintenanceWindow - maps the resource data to a maintenance window
+func expandMaintenanceWindow(d *schema.ResourceData) (maintenanceWindowUpdate, error) {
+	duration, r, err := expandSchedule(map[string]interface{}{
+		"duration": d.Get("duration"),
+		"rrule":    d.Get("rrule"),
+		"start":    d.Get("start"),
+		"timezone": d.Get("timezone"),
+	})
+	if err != nil {
+		return maintenanceWindowUpdate{}, err
+	}
+
+	return maintenanceWindowUpdate{
+		Title:    d.Get("title").(string),
+		Enabled:  d.Get("enabled").(bool),
+		Duration: duration,
+		RRule:    r,
+	}, nil
+}
+
+// maintenanceWindowActiveUntil - returns the end of the occurrence of a
+// maintenance window that is running at the given time, or an empty string
+// when the rules of the space are not snoozed by it
+func maintenanceWindowActiveUntil(w *maintenanceWindow, now time.Time) string {
+	if !w.Enabled || w.Status != "running" {
+		return ""
+	}
+
+	for _, e := range w.Events {
+		gte, err := time.Parse(time.RFC3339, e.Gte)
+		if err != nil {
+			continue
+		}
+		lte, err := time.Parse(time.RFC3339, e.Lte)
+		if err != nil {
+			continue
+		}
+
+		if !now.Before(gte) && now.Before(lte) {
+			return e.Lte
+		}
+	}
+	return ""
+}
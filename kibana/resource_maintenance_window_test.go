@@ -0,0 +1,162 @@
+package kibana
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccKibanaMaintenanceWindow_basic(t *testing.T) {
+	resourceName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckKibanaMaintenanceWindowDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKibanaMaintenanceWindow(resourceName, "2h", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKibanaMaintenanceWindowExists(resourceName),
+					resource.TestCheckResourceAttr("kibana_maintenance_window."+resourceName, "rrule", "FREQ=WEEKLY;BYDAY=SA"),
+					resource.TestCheckResourceAttr("kibana_maintenance_window."+resourceName, "status", "upcoming"),
+				),
+			},
+			{
+				Config: testAccKibanaMaintenanceWindow(resourceName, "3h", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKibanaMaintenanceWindowExists(resourceName),
+					resource.TestCheckResourceAttr("kibana_maintenance_window."+resourceName, "duration", "3h0m0s"),
+					resource.TestCheckResourceAttr("kibana_maintenance_window."+resourceName, "enabled", "false"),
+				),
+			},
+			{
+				ResourceName:      "kibana_maintenance_window." + resourceName,
+				ImportState:       true,
+				ImportStateIdFunc: testAccSpaceObjectImportID("kibana_maintenance_window." + resourceName),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestResourceMaintenanceWindowCreate_disabled(t *testing.T) {
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Kibana", r.Header.Get("x-elastic-internal-origin"))
+		body, _ := ioutil.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+string(body))
+		fmt.Fprint(w, `{
+			"id": "mw1",
+			"title": "weekly release",
+			"enabled": false,
+			"duration": 7200000,
+			"r_rule": {"dtstart": "2024-05-04T22:00:00.000Z", "tzid": "UTC", "freq": 2, "byweekday": ["SA"]},
+			"status": "archived",
+			"events": []
+		}`)
+	}))
+	defer ts.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceMaintenanceWindow().Schema, map[string]interface{}{
+		"title":    "weekly release",
+		"enabled":  false,
+		"duration": "2h",
+		"rrule":    "FREQ=WEEKLY;BYDAY=SA",
+		"start":    "2024-05-04T22:00:00Z",
+	})
+
+	diags := resourceMaintenanceWindowCreate(context.Background(), d, testClient(t, ts))
+	assert.Empty(t, diags)
+	assert.Equal(t, []string{
+		`POST /s/testSpace/internal/alerting/rules/maintenance_window {"title":"weekly release","duration":7200000,"r_rule":{"dtstart":"2024-05-04T22:00:00Z","tzid":"UTC","freq":2,"byweekday":["SA"]}}`,
+		`POST /s/testSpace/internal/alerting/rules/maintenance_window/mw1 {"title":"weekly release","enabled":false,"duration":7200000,"r_rule":{"dtstart":"2024-05-04T22:00:00Z","tzid":"UTC","freq":2,"byweekday":["SA"]}}`,
+		`GET /s/testSpace/internal/alerting/rules/maintenance_window/mw1 `,
+	}, calls)
+	assert.Equal(t, "mw1", d.Id())
+	assert.Equal(t, "2h0m0s", d.Get("duration"))
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=SA", d.Get("rrule"))
+	assert.Equal(t, "archived", d.Get("status"))
+	assert.Equal(t, "", d.Get("is_snoozed_until"))
+}
+
+func TestMaintenanceWindowActiveUntil(t *testing.T) {
+	w := &maintenanceWindow{
+		Enabled: true,
+		Status:  "running",
+		Events: []maintenanceWindowEvent{
+			{Gte: "2024-05-04T22:00:00.000Z", Lte: "2024-05-05T00:00:00.000Z"},
+			{Gte: "2024-05-11T22:00:00.000Z", Lte: "2024-05-12T00:00:00.000Z"},
+		},
+	}
+
+	now := time.Date(2024, 5, 11, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2024-05-12T00:00:00.000Z", maintenanceWindowActiveUntil(w, now))
+	assert.Equal(t, "", maintenanceWindowActiveUntil(w, now.Add(2*time.Hour)))
+
+	w.Status = "upcoming"
+	assert.Equal(t, "", maintenanceWindowActiveUntil(w, now))
+}
+
+func testAccCheckKibanaMaintenanceWindowExists(resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["kibana_maintenance_window."+resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		c := testAccProvider.Meta().(*apiClient)
+		_, err := c.getMaintenanceWindow(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+func testAccKibanaMaintenanceWindow(resourceName string, duration string, enabled bool) string {
+	return fmt.Sprintf(`
+		resource "kibana_maintenance_window" "%s" {
+		  title    = "%s"
+		  enabled  = %t
+		  start    = "2030-01-05T22:00:00Z"
+		  duration = "%s"
+		  rrule    = "FREQ=WEEKLY;BYDAY=SA"
+		}`, resourceName, resourceName, enabled, duration)
+}
+
+func testAccCheckKibanaMaintenanceWindowDestroy(s *terraform.State) error {
+	c := testAccProvider.Meta().(*apiClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "kibana_maintenance_window" {
+			continue
+		}
+
+		_, err := c.getMaintenanceWindow(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("maintenance window (%s) still exists", rs.Primary.ID)
+		}
+
+		if !strings.Contains(err.Error(), "404") {
+			return err
+		}
+	}
+	return nil
+}
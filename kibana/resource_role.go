@@ -0,0 +1,423 @@
+package kibana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/renato0307/terraform-provider-kibana/kibana/internal/utils"
+)
+
+func resourceRole() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRoleCreate,
+		ReadContext:   resourceRoleRead,
+		UpdateContext: resourceRoleUpdate,
+		DeleteContext: resourceRoleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourceRoleCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"elasticsearch": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cluster": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"indices": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"allow_restricted_indices": {
+										Type:     schema.TypeBool,
+										Optional: true,
+									},
+									"field_security": {
+										Type:     schema.TypeList,
+										Optional: true,
+										MaxItems: 1,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"except": {
+													Type:     schema.TypeSet,
+													Optional: true,
+													Elem: &schema.Schema{
+														Type: schema.TypeString,
+													},
+												},
+												"grant": {
+													Type:     schema.TypeSet,
+													Optional: true,
+													Elem: &schema.Schema{
+														Type: schema.TypeString,
+													},
+												},
+											},
+										},
+									},
+									"names": {
+										Type:     schema.TypeSet,
+										Required: true,
+										Elem: &schema.Schema{
+											Type: schema.TypeString,
+										},
+									},
+									"privileges": {
+										Type:     schema.TypeSet,
+										Required: true,
+										Elem: &schema.Schema{
+											Type: schema.TypeString,
+										},
+									},
+									"query": {
+										Type:             schema.TypeString,
+										Optional:         true,
+										ValidateFunc:     validation.StringIsJSON,
+										DiffSuppressFunc: utils.SuppressEquivalentJSONDiffs,
+									},
+								},
+							},
+						},
+						"run_as": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+			"kibana": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"base": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"feature": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"privileges": {
+										Type:     schema.TypeSet,
+										Required: true,
+										Elem: &schema.Schema{
+											Type: schema.TypeString,
+										},
+									},
+								},
+							},
+						},
+						"spaces": {
+							Type:     schema.TypeSet,
+							Required: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+			"metadata": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: utils.SuppressEquivalentJSONDiffs,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+// resourceRoleCustomizeDiff - fails the plan when Kibana privileges mix base
+// and feature privileges
+func resourceRoleCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	return validateRoleKibanaPrivileges(d.Get("kibana").(*schema.Set))
+}
+
+// validateRoleKibanaPrivileges - checks that each kibana block has either base
+// or feature privileges
+func validateRoleKibanaPrivileges(kibana *schema.Set) error {
+	for _, v := range kibana.List() {
+		v := v.(map[string]interface{})
+		if v["base"].(*schema.Set).Len() > 0 && v["feature"].(*schema.Set).Len() > 0 {
+			return fmt.Errorf("kibana privileges for spaces %v cannot have both base and feature privileges", expandStringSet(v["spaces"].(*schema.Set)))
+		}
+	}
+	return nil
+}
+
+// resourceRoleCreate - creates a role
+func resourceRoleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+
+	// maps the resource data to a role
+	r, err := expandRole(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// calls API to create the role
+	c := m.(*apiClient)
+	err = c.putRole(r)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(r.Name)
+
+	// reads the created role
+	return resourceRoleRead(ctx, d, m)
+}
+
+// resourceRoleRead - reads a role
+func resourceRoleRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+
+	// reads the role from Kibana
+	c := m.(*apiClient)
+	r, err := c.getRole(d.Id())
+	if isNotFound(err) {
+		return resourceNotFound(d, "role")
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// maps the role to the resource data
+	err = flattenRole(d, r)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// resourceRoleUpdate - updates a role
+func resourceRoleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+
+	// maps the resource data to a role
+	r, err := expandRole(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// calls API to update the role
+	c := m.(*apiClient)
+	err = c.putRole(r)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// reads the updated role and returns
+	return resourceRoleRead(ctx, d, m)
+}
+
+// resourceRoleDelete - deletes a role
+func resourceRoleDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+
+	c := m.(*apiClient)
+	err := c.deleteRole(d.Id())
+	if err != nil && !isNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	d.SetId("")
+
+	return nil
+}
+
+// Expand and flatten functions
+
+// expandRole - maps the resource data to a role
+func expandRole(d *schema.ResourceData) (role, error) {
+	r := role{
+		Name: d.Get("name").(string),
+		Elasticsearch: roleElasticsearch{
+			Cluster: []string{},
+			Indices: []roleIndices{},
+			RunAs:   []string{},
+		},
+		Kibana: []roleKibana{},
+	}
+
+	if v, ok := d.GetOk("metadata"); ok {
+		err := json.Unmarshal([]byte(v.(string)), &r.Metadata)
+		if err != nil {
+			return role{}, err
+		}
+	}
+
+	if v, ok := d.GetOk("elasticsearch"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+		es := v.([]interface{})[0].(map[string]interface{})
+		r.Elasticsearch.Cluster = expandStringSet(es["cluster"].(*schema.Set))
+		r.Elasticsearch.RunAs = expandStringSet(es["run_as"].(*schema.Set))
+
+		for _, v := range es["indices"].([]interface{}) {
+			v := v.(map[string]interface{})
+			indices := roleIndices{
+				Names:                  expandStringSet(v["names"].(*schema.Set)),
+				Privileges:             expandStringSet(v["privileges"].(*schema.Set)),
+				AllowRestrictedIndices: v["allow_restricted_indices"].(bool),
+			}
+			if query := v["query"].(string); query != "" {
+				// sent as a string, which Elasticsearch returns unchanged
+				q, err := json.Marshal(query)
+				if err != nil {
+					return role{}, err
+				}
+				indices.Query = q
+			}
+			if fs := v["field_security"].([]interface{}); len(fs) > 0 && fs[0] != nil {
+				fs := fs[0].(map[string]interface{})
+				indices.FieldSecurity = &roleFieldSecurity{
+					Grant:  expandStringSet(fs["grant"].(*schema.Set)),
+					Except: expandStringSet(fs["except"].(*schema.Set)),
+				}
+			}
+			r.Elasticsearch.Indices = append(r.Elasticsearch.Indices, indices)
+		}
+	}
+
+	err := validateRoleKibanaPrivileges(d.Get("kibana").(*schema.Set))
+	if err != nil {
+		return role{}, err
+	}
+
+	for _, v := range d.Get("kibana").(*schema.Set).List() {
+		v := v.(map[string]interface{})
+		kibana := roleKibana{
+			Base:    expandStringSet(v["base"].(*schema.Set)),
+			Feature: map[string][]string{},
+			Spaces:  expandStringSet(v["spaces"].(*schema.Set)),
+		}
+		for _, f := range v["feature"].(*schema.Set).List() {
+			f := f.(map[string]interface{})
+			kibana.Feature[f["name"].(string)] = expandStringSet(f["privileges"].(*schema.Set))
+		}
+		r.Kibana = append(r.Kibana, kibana)
+	}
+
+	return r, nil
+}
+
+// flattenRole - fills the resource data from a role
+func flattenRole(d *schema.ResourceData, r *role) error {
+	_ = d.Set("name", r.Name)
+
+	if len(r.Metadata) > 0 {
+		metadata, err := json.Marshal(r.Metadata)
+		if err != nil {
+			return err
+		}
+		_ = d.Set("metadata", string(metadata))
+	} else {
+		_ = d.Set("metadata", "")
+	}
+
+	es := r.Elasticsearch
+	if len(es.Cluster) > 0 || len(es.Indices) > 0 || len(es.RunAs) > 0 {
+		var indices []interface{}
+		for _, i := range es.Indices {
+			query, err := flattenRoleQuery(i.Query)
+			if err != nil {
+				return err
+			}
+
+			var fieldSecurity []interface{}
+			if i.FieldSecurity != nil {
+				fieldSecurity = append(fieldSecurity, map[string]interface{}{
+					"grant":  i.FieldSecurity.Grant,
+					"except": i.FieldSecurity.Except,
+				})
+			}
+			indices = append(indices, map[string]interface{}{
+				"allow_restricted_indices": i.AllowRestrictedIndices,
+				"field_security":           fieldSecurity,
+				"names":                    i.Names,
+				"privileges":               i.Privileges,
+				"query":                    query,
+			})
+		}
+		err := d.Set("elasticsearch", []interface{}{
+			map[string]interface{}{
+				"cluster": es.Cluster,
+				"indices": indices,
+				"run_as":  es.RunAs,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		_ = d.Set("elasticsearch", nil)
+	}
+
+	var kibana []interface{}
+	for _, k := range r.Kibana {
+		var features []interface{}
+		for name, privileges := range k.Feature {
+			features = append(features, map[string]interface{}{
+				"name":       name,
+				"privileges": privileges,
+			})
+		}
+		kibana = append(kibana, map[string]interface{}{
+			"base":    k.Base,
+			"feature": features,
+			"spaces":  k.Spaces,
+		})
+	}
+	return d.Set("kibana", kibana)
+}
+
+// flattenRoleQuery - returns a document level security query as a JSON string
+//
+// Elasticsearch returns the query as it was given, either as a string holding
+// the JSON query or as a JSON object, like for roles created in the UI.
+func flattenRoleQuery(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+
+	var query string
+	if err := json.Unmarshal(raw, &query); err == nil {
+		return query, nil
+	}
+
+	return utils.NormalizeJSON(string(raw))
+}
+
+// expandStringSet - converts a set of strings to a slice
+func expandStringSet(set *schema.Set) []string {
+	values := []string{}
+	for _, v := range set.List() {
+		values = append(values, v.(string))
+	}
+	return values
+}
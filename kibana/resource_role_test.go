@@ -0,0 +1,243 @@
+package kibana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccKibanaRole_basic(t *testing.T) {
+	resourceName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckKibanaRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKibanaRole(resourceName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKibanaRoleExists(resourceName),
+					resource.TestCheckResourceAttr("kibana_role."+resourceName, "elasticsearch.0.indices.#", "1"),
+					resource.TestCheckResourceAttr("kibana_role."+resourceName, "kibana.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "kibana_role." + resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestExpandFlattenRole(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceRole().Schema, map[string]interface{}{
+		"name":     "analysts",
+		"metadata": `{"team": "security"}`,
+		"elasticsearch": []interface{}{
+			map[string]interface{}{
+				"cluster": []interface{}{"monitor"},
+				"indices": []interface{}{
+					map[string]interface{}{
+						"names":      []interface{}{"logs-*"},
+						"privileges": []interface{}{"read", "view_index_metadata"},
+						"field_security": []interface{}{
+							map[string]interface{}{"grant": []interface{}{"*"}},
+						},
+					},
+				},
+			},
+		},
+		"kibana": []interface{}{
+			map[string]interface{}{
+				"base":   []interface{}{"read"},
+				"spaces": []interface{}{"default"},
+			},
+			map[string]interface{}{
+				"feature": []interface{}{
+					map[string]interface{}{"name": "discover", "privileges": []interface{}{"all"}},
+				},
+				"spaces": []interface{}{"security"},
+			},
+		},
+	})
+
+	r, err := expandRole(d)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "analysts", r.Name)
+	assert.Equal(t, "security", r.Metadata["team"])
+	assert.Equal(t, []string{"monitor"}, r.Elasticsearch.Cluster)
+	assert.Equal(t, []string{"*"}, r.Elasticsearch.Indices[0].FieldSecurity.Grant)
+	assert.Len(t, r.Kibana, 2)
+
+	flattened := schema.TestResourceDataRaw(t, resourceRole().Schema, map[string]interface{}{})
+	if assert.NoError(t, flattenRole(flattened, &r)) {
+		for _, key := range []string{
+			"elasticsearch.0.cluster",
+			"elasticsearch.0.indices.0.names",
+			"elasticsearch.0.indices.0.privileges",
+			"elasticsearch.0.indices.0.field_security.0.grant",
+			"kibana",
+		} {
+			assert.True(t, d.Get(key).(*schema.Set).Equal(flattened.Get(key)), key)
+		}
+		assert.JSONEq(t, d.Get("metadata").(string), flattened.Get("metadata").(string))
+	}
+}
+
+func TestExpandRole_baseAndFeature(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceRole().Schema, map[string]interface{}{
+		"name": "invalid",
+		"kibana": []interface{}{
+			map[string]interface{}{
+				"base": []interface{}{"all"},
+				"feature": []interface{}{
+					map[string]interface{}{"name": "discover", "privileges": []interface{}{"all"}},
+				},
+				"spaces": []interface{}{"default"},
+			},
+		},
+	})
+
+	_, err := expandRole(d)
+	assert.Error(t, err)
+}
+
+func TestResourceRoleDiff_baseAndFeature(t *testing.T) {
+	config := map[string]interface{}{
+		"name": "invalid",
+		"kibana": []interface{}{
+			map[string]interface{}{
+				"base": []interface{}{"all"},
+				"feature": []interface{}{
+					map[string]interface{}{"name": "discover", "privileges": []interface{}{"all"}},
+				},
+				"spaces": []interface{}{"default"},
+			},
+		},
+	}
+
+	_, err := resourceRole().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "cannot have both base and feature privileges")
+	}
+}
+
+func TestFlattenRole_query(t *testing.T) {
+	r := role{}
+	err := json.Unmarshal([]byte(`{
+		"name": "analysts",
+		"elasticsearch": {
+			"cluster": [],
+			"indices": [
+				{"names": ["logs-*"], "privileges": ["read"], "query": {"match": {"team": "security"}}},
+				{"names": ["metrics-*"], "privileges": ["read"], "query": "{\"match\": {\"team\": \"ops\"}}"}
+			],
+			"run_as": []
+		},
+		"kibana": []
+	}`), &r)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceRole().Schema, map[string]interface{}{})
+	if assert.NoError(t, flattenRole(d, &r)) {
+		assert.Equal(t, `{"match":{"team":"security"}}`, d.Get("elasticsearch.0.indices.0.query"))
+		assert.Equal(t, `{"match": {"team": "ops"}}`, d.Get("elasticsearch.0.indices.1.query"))
+	}
+
+	expanded, err := expandRole(d)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `"{\"match\":{\"team\":\"security\"}}"`, string(expanded.Elasticsearch.Indices[0].Query))
+	}
+}
+
+func testAccCheckKibanaRoleExists(resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["kibana_role."+resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		c := testAccProvider.Meta().(*apiClient)
+		_, err := c.getRole(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+func testAccKibanaRole(resourceName string) string {
+	return fmt.Sprintf(`
+			resource "kibana_role" "%s" {
+			  name = "test_role_%s"
+
+			  elasticsearch {
+				cluster = ["monitor"]
+
+				indices {
+				  names      = ["logs-*"]
+				  privileges = ["read", "view_index_metadata"]
+				}
+			  }
+
+			  kibana {
+				base   = ["read"]
+				spaces = ["default"]
+			  }
+
+			  kibana {
+				feature {
+				  name       = "discover"
+				  privileges = ["all"]
+				}
+				feature {
+				  name       = "dashboard"
+				  privileges = ["read"]
+				}
+				spaces = ["*"]
+			  }
+			}`, resourceName, resourceName)
+}
+
+func testAccCheckKibanaRoleDestroy(s *terraform.State) error {
+	c := testAccProvider.Meta().(*apiClient)
+
+	// loop through the resources in state, verifying each role
+	// is destroyed
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "kibana_role" {
+			continue
+		}
+
+		_, err := c.getRole(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("role (%s) still exists", rs.Primary.ID)
+		}
+
+		// If the error is equivalent to 404 not found, the role is destroyed.
+		// Otherwise return the error
+		if !strings.Contains(err.Error(), "404") {
+			return err
+		}
+	}
+	return nil
+}
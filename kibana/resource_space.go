@@ -0,0 +1,143 @@
+package kibana
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceSpace() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSpaceCreate,
+		ReadContext:   resourceSpaceRead,
+		UpdateContext: resourceSpaceUpdate,
+		DeleteContext: resourceSpaceDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"color": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"disabled_features": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"image_url": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"initials": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"space_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+// resourceSpaceCreate - creates a space
+func resourceSpaceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+
+	// calls API to create the space
+	c := m.(*apiClient)
+	newSpace, err := c.createSpace(expandSpace(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(newSpace.ID)
+
+	// reads the created space
+	return resourceSpaceRead(ctx, d, m)
+}
+
+// resourceSpaceRead - reads a space
+func resourceSpaceRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+
+	// reads the space from Kibana
+	c := m.(*apiClient)
+	s, err := c.getSpace(d.Id())
+	if isNotFound(err) {
+		return resourceNotFound(d, "space")
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// maps the space to the resource data
+	_ = d.Set("color", s.Color)
+	_ = d.Set("description", s.Description)
+	_ = d.Set("disabled_features", s.DisabledFeatures)
+	_ = d.Set("image_url", s.ImageURL)
+	_ = d.Set("initials", s.Initials)
+	_ = d.Set("name", s.Name)
+	_ = d.Set("space_id", s.ID)
+
+	return nil
+}
+
+// resourceSpaceUpdate - updates a space
+func resourceSpaceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+
+	// calls API to update the space
+	c := m.(*apiClient)
+	_, err := c.updateSpace(expandSpace(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// reads the updated space and returns
+	return resourceSpaceRead(ctx, d, m)
+}
+
+// resourceSpaceDelete - deletes a space
+func resourceSpaceDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+
+	c := m.(*apiClient)
+	err := c.deleteSpace(d.Id())
+	if err != nil && !isNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	d.SetId("")
+
+	return nil
+}
+
+// expandSpace - maps the resource data to a space
+func expandSpace(d *schema.ResourceData) space {
+	disabledFeatures := []string{}
+	for _, feature := range d.Get("disabled_features").(*schema.Set).List() {
+		disabledFeatures = append(disabledFeatures, feature.(string))
+	}
+
+	return space{
+		ID:               d.Get("space_id").(string),
+		Name:             d.Get("name").(string),
+		Description:      d.Get("description").(string),
+		Color:            d.Get("color").(string),
+		Initials:         d.Get("initials").(string),
+		ImageURL:         d.Get("image_url").(string),
+		DisabledFeatures: disabledFeatures,
+	}
+}
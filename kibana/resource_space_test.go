@@ -0,0 +1,99 @@
+package kibana
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccKibanaSpace_basic(t *testing.T) {
+	resourceName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckKibanaSpaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKibanaSpace(resourceName, "Test space"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKibanaSpaceExists(resourceName),
+					resource.TestCheckResourceAttr("kibana_space."+resourceName, "description", "Test space"),
+					resource.TestCheckResourceAttr("kibana_space."+resourceName, "disabled_features.#", "1"),
+				),
+			},
+			{
+				Config: testAccKibanaSpace(resourceName, "Updated test space"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("kibana_space."+resourceName, "description", "Updated test space"),
+				),
+			},
+			{
+				ResourceName:      "kibana_space." + resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckKibanaSpaceExists(resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["kibana_space."+resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		c := testAccProvider.Meta().(*apiClient)
+		_, err := c.getSpace(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+func testAccKibanaSpace(resourceName, description string) string {
+	return fmt.Sprintf(`
+			resource "kibana_space" "%s" {
+			  space_id          = "test-space-%s"
+			  name              = "Test space %s"
+			  description       = "%s"
+			  color             = "#aabbcc"
+			  initials          = "TS"
+			  disabled_features = ["dev_tools"]
+			}`, resourceName, strings.ToLower(resourceName), resourceName, description)
+}
+
+func testAccCheckKibanaSpaceDestroy(s *terraform.State) error {
+	c := testAccProvider.Meta().(*apiClient)
+
+	// loop through the resources in state, verifying each space
+	// is destroyed
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "kibana_space" {
+			continue
+		}
+
+		_, err := c.getSpace(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("space (%s) still exists", rs.Primary.ID)
+		}
+
+		// If the error is equivalent to 404 not found, the space is destroyed.
+		// Otherwise return the error
+		if !strings.Contains(err.Error(), "404") {
+			return err
+		}
+	}
+	return nil
+}
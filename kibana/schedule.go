@@ -0,0 +1,305 @@
+package kibana
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// rRule - recurrence of a schedule, as used by rule snoozes and maintenance
+// windows
+//
+// It follows the RFC 5545 recurrence rule, with the frequency as a number.
+type rRule struct {
+	Dtstart    string        `json:"dtstart"`
+	Tzid       string        `json:"tzid"`
+	Freq       *int          `json:"freq,omitempty"`
+	Interval   int           `json:"interval,omitempty"`
+	Count      int           `json:"count,omitempty"`
+	Until      string        `json:"until,omitempty"`
+	Byweekday  []interface{} `json:"byweekday,omitempty"`
+	Bymonthday []int         `json:"bymonthday,omitempty"`
+	Bymonth    []int         `json:"bymonth,omitempty"`
+}
+
+// rRuleFrequencies - RFC 5545 frequencies, indexed by their number in Kibana
+var rRuleFrequencies = []string{"YEARLY", "MONTHLY", "WEEKLY", "DAILY", "HOURLY", "MINUTELY", "SECONDLY"}
+
+// rRuleWeekdays - RFC 5545 weekdays, indexed by their number in Kibana
+var rRuleWeekdays = []string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}
+
+// rRuleUntilFormat - format of UNTIL in an RFC 5545 recurrence rule
+const rRuleUntilFormat = "20060102T150405Z"
+
+var rRuleWeekdayRegexp = regexp.MustCompile(`^[+-]?[0-9]{0,2}(MO|TU|WE|TH|FR|SA|SU)$`)
+
+// scheduleSchema - attributes of a schedule, with a start, a duration and an
+// optional recurrence
+func scheduleSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"duration": {
+			Type:             schema.TypeString,
+			Required:         true,
+			ValidateFunc:     validateScheduleDuration,
+			DiffSuppressFunc: suppressEquivalentScheduleDuration,
+		},
+		"rrule": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateFunc:     validateScheduleRRule,
+			DiffSuppressFunc: suppressEquivalentScheduleRRule,
+		},
+		"start": {
+			Type:             schema.TypeString,
+			Required:         true,
+			ValidateFunc:     validation.IsRFC3339Time,
+			DiffSuppressFunc: suppressEquivalentScheduleStart,
+		},
+		"timezone": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "UTC",
+			ValidateFunc: validateScheduleTimezone,
+		},
+	}
+}
+
+// expandSchedule - maps the schedule attributes to a duration in milliseconds
+// and a recurrence
+func expandSchedule(s map[string]interface{}) (int64, rRule, error) {
+	duration, err := time.ParseDuration(s["duration"].(string))
+	if err != nil {
+		return 0, rRule{}, err
+	}
+
+	r, err := parseRRule(s["rrule"].(string))
+	if err != nil {
+		return 0, rRule{}, err
+	}
+	r.Dtstart = s["start"].(string)
+	r.Tzid = s["timezone"].(string)
+
+	return duration.Milliseconds(), r, nil
+}
+
+// flattenSchedule - maps a duration in milliseconds and a recurrence to the
+// schedule attributes
+func flattenSchedule(duration int64, r rRule) map[string]interface{} {
+	return map[string]interface{}{
+		"duration": (time.Duration(duration) * time.Millisecond).String(),
+		"rrule":    formatRRule(r),
+		"start":    r.Dtstart,
+		"timezone": r.Tzid,
+	}
+}
+
+// scheduleHashKey - returns a key identifying the schedule attributes, the same
+// for equivalent durations, starts and recurrences
+func scheduleHashKey(s map[string]interface{}) string {
+	duration, _ := s["duration"].(string)
+	if d, err := time.ParseDuration(duration); err == nil {
+		duration = d.String()
+	}
+
+	rrule, _ := s["rrule"].(string)
+	if r, err := parseRRule(rrule); err == nil {
+		rrule = formatRRule(r)
+	}
+
+	start, _ := s["start"].(string)
+	if t, err := time.Parse(time.RFC3339, start); err == nil {
+		start = t.UTC().Format(time.RFC3339)
+	}
+
+	timezone, _ := s["timezone"].(string)
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	return fmt.Sprintf("%s-%s-%s-%s", duration, rrule, start, timezone)
+}
+
+// parseRRule - parses an RFC 5545 recurrence rule
+//
+// An empty rule is a single occurrence.
+func parseRRule(s string) (rRule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return rRule{Count: 1}, nil
+	}
+
+	r := rRule{}
+	for _, part := range strings.Split(s, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return rRule{}, fmt.Errorf("invalid rrule part %q, expected <NAME>=<VALUE>", part)
+		}
+
+		name, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+		switch name {
+		case "FREQ":
+			freq := indexOf(rRuleFrequencies, value)
+			if freq < 0 {
+				return rRule{}, fmt.Errorf("invalid rrule FREQ %q, expected one of %s", value, strings.Join(rRuleFrequencies, ", "))
+			}
+			r.Freq = &freq
+		case "INTERVAL", "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return rRule{}, fmt.Errorf("invalid rrule %s %q, expected a positive number", name, value)
+			}
+			if name == "INTERVAL" {
+				r.Interval = n
+			} else {
+				r.Count = n
+			}
+		case "UNTIL":
+			until, err := time.Parse(rRuleUntilFormat, value)
+			if err != nil {
+				return rRule{}, fmt.Errorf("invalid rrule UNTIL %q, expected a UTC date like 20240131T235959Z", value)
+			}
+			r.Until = until.Format(time.RFC3339)
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				if !rRuleWeekdayRegexp.MatchString(day) {
+					return rRule{}, fmt.Errorf("invalid rrule BYDAY %q", day)
+				}
+				r.Byweekday = append(r.Byweekday, day)
+			}
+		case "BYMONTHDAY", "BYMONTH":
+			min, max := -31, 31
+			if name == "BYMONTH" {
+				min, max = 1, 12
+			}
+			for _, v := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(v)
+				if err != nil || n == 0 || n < min || n > max {
+					return rRule{}, fmt.Errorf("invalid rrule %s %q", name, v)
+				}
+				if name == "BYMONTH" {
+					r.Bymonth = append(r.Bymonth, n)
+				} else {
+					r.Bymonthday = append(r.Bymonthday, n)
+				}
+			}
+		default:
+			return rRule{}, fmt.Errorf("unsupported rrule part %q", name)
+		}
+	}
+
+	if r.Freq == nil {
+		return rRule{}, fmt.Errorf("rrule %q has no FREQ", s)
+	}
+	if r.Count > 0 && r.Until != "" {
+		return rRule{}, fmt.Errorf("rrule %q cannot have both COUNT and UNTIL", s)
+	}
+	return r, nil
+}
+
+// formatRRule - returns the RFC 5545 recurrence rule of a recurrence, or an
+// empty string for a single occurrence
+func formatRRule(r rRule) string {
+	if r.Freq == nil || *r.Freq < 0 || *r.Freq >= len(rRuleFrequencies) {
+		return ""
+	}
+
+	parts := []string{"FREQ=" + rRuleFrequencies[*r.Freq]}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	if until, err := time.Parse(time.RFC3339, r.Until); err == nil {
+		parts = append(parts, "UNTIL="+until.UTC().Format(rRuleUntilFormat))
+	}
+	if len(r.Byweekday) > 0 {
+		days := make([]string, 0, len(r.Byweekday))
+		for _, day := range r.Byweekday {
+			// Kibana also accepts the weekdays as numbers, starting on Monday
+			if n, ok := day.(float64); ok && int(n) >= 0 && int(n) < len(rRuleWeekdays) {
+				day = rRuleWeekdays[int(n)]
+			}
+			days = append(days, fmt.Sprint(day))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.Bymonthday) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.Bymonthday))
+	}
+	if len(r.Bymonth) > 0 {
+		parts = append(parts, "BYMONTH="+joinInts(r.Bymonth))
+	}
+	return strings.Join(parts, ";")
+}
+
+func validateScheduleDuration(v interface{}, k string) ([]string, []error) {
+	duration, err := time.ParseDuration(v.(string))
+	if err != nil || duration < time.Minute {
+		return nil, []error{fmt.Errorf("expected %s to be a duration of at least 1m, like 30m or 2h, got %q", k, v)}
+	}
+	return nil, nil
+}
+
+func validateScheduleRRule(v interface{}, k string) ([]string, []error) {
+	if _, err := parseRRule(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
+
+func validateScheduleTimezone(v interface{}, k string) ([]string, []error) {
+	if _, err := time.LoadLocation(v.(string)); err != nil || v.(string) == "" {
+		return nil, []error{fmt.Errorf("expected %s to be an IANA time zone, got %q", k, v)}
+	}
+	return nil, nil
+}
+
+func suppressEquivalentScheduleDuration(_, old, new string, _ *schema.ResourceData) bool {
+	o, err := time.ParseDuration(old)
+	if err != nil {
+		return false
+	}
+	n, err := time.ParseDuration(new)
+	return err == nil && o == n
+}
+
+func suppressEquivalentScheduleRRule(_, old, new string, _ *schema.ResourceData) bool {
+	o, err := parseRRule(old)
+	if err != nil {
+		return false
+	}
+	n, err := parseRRule(new)
+	return err == nil && formatRRule(o) == formatRRule(n)
+}
+
+func suppressEquivalentScheduleStart(_, old, new string, _ *schema.ResourceData) bool {
+	o, err := time.Parse(time.RFC3339, old)
+	if err != nil {
+		return false
+	}
+	n, err := time.Parse(time.RFC3339, new)
+	return err == nil && o.Equal(n)
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func joinInts(values []int) string {
+	s := make([]string, 0, len(values))
+	for _, v := range values {
+		s = append(s, strconv.Itoa(v))
+	}
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,97 @@
+package kibana
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormatRRule(t *testing.T) {
+	tests := []struct {
+		rrule    string
+		json     string
+		expected string
+	}{
+		{"", `{"dtstart":"","tzid":"","count":1}`, ""},
+		{"FREQ=DAILY", `{"dtstart":"","tzid":"","freq":3}`, "FREQ=DAILY"},
+		{"RRULE:freq=weekly;interval=1;byday=MO,TH;count=10", `{"dtstart":"","tzid":"","freq":2,"interval":1,"count":10,"byweekday":["MO","TH"]}`, "FREQ=WEEKLY;COUNT=10;BYDAY=MO,TH"},
+		{"FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=1,-1;UNTIL=20241231T235959Z", `{"dtstart":"","tzid":"","freq":1,"interval":2,"until":"2024-12-31T23:59:59Z","bymonthday":[1,-1]}`, "FREQ=MONTHLY;INTERVAL=2;UNTIL=20241231T235959Z;BYMONTHDAY=1,-1"},
+		{"FREQ=YEARLY;BYMONTH=12;BYDAY=+1MO", `{"dtstart":"","tzid":"","freq":0,"byweekday":["+1MO"],"bymonth":[12]}`, "FREQ=YEARLY;BYDAY=+1MO;BYMONTH=12"},
+	}
+
+	for _, test := range tests {
+		r, err := parseRRule(test.rrule)
+		if !assert.NoError(t, err, test.rrule) {
+			continue
+		}
+
+		b, _ := json.Marshal(r)
+		assert.Equal(t, test.json, string(b), test.rrule)
+		assert.Equal(t, test.expected, formatRRule(r), test.rrule)
+	}
+}
+
+func TestParseRRule_invalid(t *testing.T) {
+	for _, rrule := range []string{
+		"COUNT=2",
+		"FREQ=FORTNIGHTLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20241231T235959Z",
+		"FREQ=DAILY;UNTIL=2024-12-31",
+		"FREQ=WEEKLY;BYDAY=MONDAY",
+		"FREQ=YEARLY;BYMONTH=13",
+		"FREQ=DAILY;BYHOUR=10",
+		"FREQ",
+	} {
+		_, err := parseRRule(rrule)
+		assert.Error(t, err, rrule)
+	}
+}
+
+func TestFormatRRule_numericWeekdays(t *testing.T) {
+	r := rRule{}
+	_ = json.Unmarshal([]byte(`{"freq": 2, "byweekday": [0, 4]}`), &r)
+
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO,FR", formatRRule(r))
+}
+
+func TestExpandFlattenSchedule(t *testing.T) {
+	s := map[string]interface{}{
+		"duration": "90m",
+		"rrule":    "FREQ=WEEKLY;BYDAY=SA",
+		"start":    "2024-05-04T22:00:00Z",
+		"timezone": "Europe/Lisbon",
+	}
+
+	duration, r, err := expandSchedule(s)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(5400000), duration)
+		assert.Equal(t, "2024-05-04T22:00:00Z", r.Dtstart)
+		assert.Equal(t, "Europe/Lisbon", r.Tzid)
+
+		flattened := flattenSchedule(duration, r)
+		assert.Equal(t, "1h30m0s", flattened["duration"])
+		assert.Equal(t, "FREQ=WEEKLY;BYDAY=SA", flattened["rrule"])
+		assert.Equal(t, scheduleHashKey(s), scheduleHashKey(flattened))
+	}
+}
+
+func TestScheduleHashKey(t *testing.T) {
+	a := map[string]interface{}{
+		"duration": "2h",
+		"rrule":    "FREQ=DAILY;INTERVAL=1",
+		"start":    "2024-05-04T22:00:00.000Z",
+		"timezone": "UTC",
+	}
+	b := map[string]interface{}{
+		"duration": "120m",
+		"rrule":    "freq=daily",
+		"start":    "2024-05-04T23:00:00+01:00",
+		"timezone": "UTC",
+	}
+	assert.Equal(t, scheduleHashKey(a), scheduleHashKey(b))
+
+	b["timezone"] = "Europe/Lisbon"
+	assert.NotEqual(t, scheduleHashKey(a), scheduleHashKey(b))
+}
@@ -0,0 +1,73 @@
+package kibana
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// tlsOptions - TLS settings used to reach Kibana
+type tlsOptions struct {
+	caCerts    string
+	clientCert string
+	clientKey  string
+	insecure   bool
+}
+
+// isSet - checks if any TLS setting differs from the defaults
+func (o tlsOptions) isSet() bool {
+	return o.caCerts != "" || o.clientCert != "" || o.clientKey != "" || o.insecure
+}
+
+// newTLSConfig - builds the TLS configuration for the HTTP transport
+func newTLSConfig(o tlsOptions) (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: o.insecure,
+	}
+
+	if o.caCerts != "" {
+		caCerts, err := readPEM(o.caCerts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read ca_certs: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caCerts) {
+			return nil, fmt.Errorf("ca_certs does not contain any valid PEM certificate")
+		}
+		config.RootCAs = pool
+	}
+
+	if o.clientCert != "" || o.clientKey != "" {
+		clientCert, err := readPEM(o.clientCert)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read client_cert: %w", err)
+		}
+		clientKey, err := readPEM(o.clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read client_key: %w", err)
+		}
+
+		cert, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// readPEM - returns the PEM content of a value that is either PEM or a file path
+func readPEM(value string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(value), "-----BEGIN") {
+		return []byte(value), nil
+	}
+
+	return ioutil.ReadFile(value)
+}
@@ -0,0 +1,81 @@
+package kibana
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	certPEM, keyPEM := testCertificate(t)
+
+	dir, err := ioutil.TempDir("", "kibana-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	if err := ioutil.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// inline PEM and file paths can be mixed
+	config, err := newTLSConfig(tlsOptions{
+		caCerts:    string(certPEM),
+		clientCert: certFile,
+		clientKey:  string(keyPEM),
+		insecure:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, config.InsecureSkipVerify)
+	assert.NotNil(t, config.RootCAs)
+	assert.Len(t, config.Certificates, 1)
+
+	_, err = newTLSConfig(tlsOptions{caCerts: "-----BEGIN CERTIFICATE-----\nnot a certificate\n-----END CERTIFICATE-----"})
+	assert.Error(t, err)
+
+	_, err = newTLSConfig(tlsOptions{caCerts: filepath.Join(dir, "missing.pem")})
+	assert.Error(t, err)
+}
+
+func testCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kibana"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM
+}
@@ -0,0 +1,26 @@
+package kibana
+
+import (
+	"net/http"
+)
+
+// apiKeyTransport - sets the API key authorization header on every request
+//
+// The go-kibana client always uses basic authentication, so the header it
+// sets is replaced here before the request is sent.
+type apiKeyTransport struct {
+	apiKey string
+	next   http.RoundTripper
+}
+
+// RoundTrip - executes a single HTTP transaction using the API key
+func (t *apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "ApiKey "+t.apiKey)
+
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(req)
+}
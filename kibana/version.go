@@ -0,0 +1,99 @@
+package kibana
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// serverVersion - version of the Kibana server
+type serverVersion struct {
+	major int
+	minor int
+	patch int
+}
+
+var (
+	// versionRuleNotifyWhen - first version supporting notify_when on rules
+	versionRuleNotifyWhen = serverVersion{7, 11, 0}
+	// versionAlertingAPI - first version with the /api/alerting and
+	// /api/actions/connector APIs, replacing /api/alerts and /api/actions/action
+	versionAlertingAPI = serverVersion{7, 13, 0}
+	// versionActionFrequency - first version supporting a frequency per rule action
+	versionActionFrequency = serverVersion{8, 6, 0}
+	// versionRuleSnoozeSchedule - first version supporting scheduled rule snoozes
+	versionRuleSnoozeSchedule = serverVersion{8, 6, 0}
+	// versionMaintenanceWindow - first version supporting maintenance windows
+	versionMaintenanceWindow = serverVersion{8, 8, 0}
+)
+
+// parseVersion - parses a version like 7.13.2 or 8.0.0-SNAPSHOT
+func parseVersion(version string) (serverVersion, error) {
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return serverVersion{}, fmt.Errorf("invalid Kibana version %q", version)
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return serverVersion{}, fmt.Errorf("invalid Kibana version %q", version)
+		}
+		numbers[i] = n
+	}
+
+	return serverVersion{numbers[0], numbers[1], numbers[2]}, nil
+}
+
+// atLeast - checks if the version is the same or newer than other
+func (v serverVersion) atLeast(other serverVersion) bool {
+	if v.major != other.major {
+		return v.major > other.major
+	}
+	if v.minor != other.minor {
+		return v.minor > other.minor
+	}
+	return v.patch >= other.patch
+}
+
+func (v serverVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+}
+
+// supports - checks if the Kibana server is at least the given version
+//
+// When the server version is unknown the latest APIs are assumed.
+func (c *apiClient) supports(minimum serverVersion) bool {
+	v, err := parseVersion(c.version)
+	if err != nil {
+		return true
+	}
+	return v.atLeast(minimum)
+}
+
+// validateAttributeVersions - fails the plan when an attribute is set but the
+// Kibana server is older than the version that introduced it
+func validateAttributeVersions(versions map[string]serverVersion) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+		c, ok := m.(*apiClient)
+		if !ok {
+			return nil
+		}
+
+		for attribute, minimum := range versions {
+			if _, ok := d.GetOk(attribute); ok && !c.supports(minimum) {
+				return fmt.Errorf("%s requires Kibana %s or later, but the server is running %s", attribute, minimum, c.version)
+			}
+		}
+
+		return nil
+	}
+}
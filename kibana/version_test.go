@@ -0,0 +1,41 @@
+package kibana
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	cases := map[string]serverVersion{
+		"7.13.2":         {7, 13, 2},
+		"8.0.0-SNAPSHOT": {8, 0, 0},
+		"7.10":           {7, 10, 0},
+	}
+	for version, expected := range cases {
+		actual, err := parseVersion(version)
+		if assert.NoError(t, err, version) {
+			assert.Equal(t, expected, actual)
+		}
+	}
+
+	for _, version := range []string{"", "8", "a.b.c", "7.13.2.1"} {
+		_, err := parseVersion(version)
+		assert.Error(t, err, version)
+	}
+}
+
+func TestServerVersionAtLeast(t *testing.T) {
+	v := serverVersion{7, 13, 2}
+	assert.True(t, v.atLeast(serverVersion{7, 13, 2}))
+	assert.True(t, v.atLeast(serverVersion{7, 11, 0}))
+	assert.True(t, v.atLeast(serverVersion{6, 99, 99}))
+	assert.False(t, v.atLeast(serverVersion{7, 13, 3}))
+	assert.False(t, v.atLeast(serverVersion{8, 0, 0}))
+}
+
+func TestSupports(t *testing.T) {
+	assert.True(t, (&apiClient{version: "8.6.0"}).supports(versionAlertingAPI))
+	assert.False(t, (&apiClient{version: "7.12.1"}).supports(versionAlertingAPI))
+	assert.True(t, (&apiClient{}).supports(versionAlertingAPI))
+}